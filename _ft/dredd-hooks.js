@@ -1,8 +1,90 @@
 var hooks = require('hooks');
+var http = require('http');
+
+// The package and the post of the /content/{uuid}/members example
+var membersExample = [
+    {
+        uuid: "1520b6b9-d466-49a0-b3ec-894b72338e7d",
+        type: "LiveBlogPackage",
+        title: "Live blog",
+        publishedDate: "2014-07-08T13:00:00.000Z"
+    },
+    {
+        uuid: "5f8b1a7c-2d3e-4b6f-9a0c-7e1d4c2b8a63",
+        type: "LiveBlogPost",
+        title: "Live blog post",
+        publishedDate: "2014-07-08T13:52:52.000Z",
+        liveBlogPackage: "1520b6b9-d466-49a0-b3ec-894b72338e7d"
+    }
+];
+
+// The content the story and content packages of the /content/{uuid}/*Packages/{packageUUID} examples are added to
+var packagesExample = [
+    {
+        uuid: "0620cfe1-e7ee-44d6-918e-e5ca278d2245",
+        title: "Profits plunge at Vatican bank",
+        publishedDate: "2014-07-08T13:52:52.000Z",
+        body: "<body></body>"
+    }
+];
+
+function putContent(transaction, content, done) {
+    var body = JSON.stringify(content);
+    var req = http.request({
+        host: transaction.host,
+        port: transaction.port,
+        method: "PUT",
+        path: "/content/" + content.uuid,
+        headers: {
+            "Content-Type": "application/json",
+            "Content-Length": Buffer.byteLength(body)
+        }
+    }, function (res) {
+        res.resume();
+        res.on("end", function () {
+            if (res.statusCode !== 200) {
+                transaction.fail = "seeding " + content.uuid + " failed with status " + res.statusCode;
+            }
+            done();
+        });
+    });
+    req.on("error", function (err) {
+        transaction.fail = "seeding " + content.uuid + " failed: " + err.message;
+        done();
+    });
+    req.end(body);
+}
 
 hooks.beforeEach(function (transaction) {
-    if (transaction.name.startsWith("Health > /__gtg")) {
+    if (transaction.name.startsWith("Health > /__gtg") ||
+        transaction.name.startsWith("Internal API > /content/__placeholders/collect")) {
         hooks.log("skipping: " + transaction.name);
         transaction.skip = true;
     }
-});
\ No newline at end of file
+});
+
+// seedingFor returns the content to write before the transaction, the packages being added by PUT and
+// removed by the following DELETE
+function seedingFor(transaction) {
+    if (transaction.name.startsWith("Internal API > /content/{uuid}/members")) {
+        return membersExample;
+    }
+    if (transaction.request.method === "PUT" &&
+        (transaction.name.startsWith("Internal API > /content/{uuid}/storyPackages/{packageUUID}") ||
+            transaction.name.startsWith("Internal API > /content/{uuid}/contentPackages/{packageUUID}"))) {
+        return packagesExample;
+    }
+    return [];
+}
+
+hooks.beforeEach(function (transaction, done) {
+    var remaining = seedingFor(transaction).slice();
+    (function next() {
+        var content = remaining.shift();
+        if (!content) {
+            done();
+            return;
+        }
+        putContent(transaction, content, next);
+    })();
+});
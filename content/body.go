@@ -0,0 +1,213 @@
+package content
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ftContentURLRegex matches the URLs of FT content, both the API ones used by <ft-content> elements
+// and the website ones used by plain <a> links, capturing the UUID of the content
+var ftContentURLRegex = regexp.MustCompile(
+	`^https?://(?:www\.|api\.)?ft\.com/content/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})/?(?:[?#].*)?$`,
+)
+
+// embeddedMediaTypes are the types of the <ft-content> elements which are embedded in the body even when
+// they are not flagged with data-embedded="true"
+var embeddedMediaTypes = map[string]bool{
+	"http://www.ft.com/ontology/content/ImageSet": true,
+	"http://www.ft.com/ontology/content/Image":    true,
+	"http://www.ft.com/ontology/content/Video":    true,
+	"http://www.ft.com/ontology/content/Graphic":  true,
+	"http://www.ft.com/ontology/content/Audio":    true,
+	"http://www.ft.com/ontology/content/ClipSet":  true,
+}
+
+// wordsPerMinute is the reading speed used to estimate the reading time of the body
+const wordsPerMinute = 200
+
+// inlineElements are the elements which do not separate words, e.g. "F<sup>1</sup>" is a single word,
+// while any other element such as a paragraph or a line break does
+var inlineElements = map[string]bool{
+	"a":          true,
+	"b":          true,
+	"strong":     true,
+	"i":          true,
+	"em":         true,
+	"u":          true,
+	"s":          true,
+	"small":      true,
+	"span":       true,
+	"sub":        true,
+	"sup":        true,
+	"ft-content": true,
+	"ft-concept": true,
+}
+
+// bodyMetrics describe the size of the body
+type bodyMetrics struct {
+	// WordCount is the number of words of the plain text of the body, a word being any sequence
+	// of characters between white spaces or elements which holds at least a letter or a digit
+	WordCount int `json:"wordCount,omitempty"`
+	// ParagraphCount is the number of <p> elements holding at least a word
+	ParagraphCount int `json:"paragraphCount,omitempty"`
+	// ReadingTimeMinutes is the time needed to read the words at wordsPerMinute, rounded up
+	ReadingTimeMinutes int `json:"readingTimeMinutes,omitempty"`
+}
+
+func newBodyMetrics(wordCount int, paragraphCount int) *bodyMetrics {
+	return &bodyMetrics{
+		WordCount:          wordCount,
+		ParagraphCount:     paragraphCount,
+		ReadingTimeMinutes: (wordCount + wordsPerMinute - 1) / wordsPerMinute,
+	}
+}
+
+// bodyData is the data Write derives from the FT XML body of the content
+type bodyData struct {
+	// links are the UUIDs of the other FT content the body links to, in the order they first appear
+	links []string
+	// embeds are the FT content embedded in the body, such as image sets, videos and graphics
+	embeds []embed
+	// metrics are only derived from a non-empty body
+	metrics *bodyMetrics
+}
+
+// embed is a content embedded in the body, Position being its 0-based index among the embeds of the body
+type embed struct {
+	UUID     string
+	Position int
+}
+
+func newBodyData() bodyData {
+	return bodyData{links: []string{}, embeds: []embed{}}
+}
+
+// embedEntries returns the embeds in the form expected by the EMBEDS managed relationship
+func (b bodyData) embedEntries() []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(b.embeds))
+	for _, e := range b.embeds {
+		entries = append(entries, map[string]interface{}{
+			"uuid":     e.UUID,
+			"position": e.Position,
+		})
+	}
+	return entries
+}
+
+// parseBody parses the FT XML body of the content with the given UUID. The parser is lenient with the
+// HTML found in bodies (unclosed void elements, HTML entities), but when the body cannot be read at all
+// an error is returned along with empty body data, and the data derived from the previous body is kept.
+func parseBody(uuid string, body string) (bodyData, error) {
+	data := newBodyData()
+	if body == "" {
+		return data, nil
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	seenLinks := map[string]bool{uuid: true}
+	addLink := func(url string) {
+		linked, ok := ftContentUUID(url)
+		if ok && !seenLinks[linked] {
+			seenLinks[linked] = true
+			data.links = append(data.links, linked)
+		}
+	}
+
+	// the same content embedded several times keeps its first position
+	seenEmbeds := map[string]bool{uuid: true}
+	addEmbed := func(url string) {
+		embedded, ok := ftContentUUID(url)
+		if ok && !seenEmbeds[embedded] {
+			seenEmbeds[embedded] = true
+			data.embeds = append(data.embeds, embed{UUID: embedded, Position: len(data.embeds)})
+		}
+	}
+
+	// text is the plain text of the body, with a space wherever an element separates words
+	var text strings.Builder
+	paragraphCount := 0
+	paragraphStart := 0
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			data.metrics = newBodyMetrics(countWords(text.String()), paragraphCount)
+			return data, nil
+		}
+		if err != nil {
+			return newBodyData(), fmt.Errorf("malformed body: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if !inlineElements[t.Name.Local] {
+				text.WriteString(" ")
+			}
+
+			switch t.Name.Local {
+			case "ft-content":
+				// embedded content is part of the body rather than linked to
+				if attr(t, "data-embedded") == "true" || embeddedMediaTypes[attr(t, "type")] {
+					addEmbed(attr(t, "url"))
+				} else {
+					addLink(attr(t, "url"))
+				}
+			case "a":
+				addLink(attr(t, "href"))
+			case "p":
+				paragraphStart = text.Len()
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" && countWords(text.String()[paragraphStart:]) > 0 {
+				paragraphCount++
+			}
+
+			if !inlineElements[t.Name.Local] {
+				text.WriteString(" ")
+			}
+		}
+	}
+}
+
+func countWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, isWordCharacter) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func isWordCharacter(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ftContentUUID returns the lower case UUID of the FT content the URL points to
+func ftContentUUID(url string) (string, bool) {
+	match := ftContentURLRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if match == nil {
+		return "", false
+	}
+	return strings.ToLower(match[1]), true
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bodyTestUUID = "ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"
+
+func readBodyFixture(t *testing.T, name string) string {
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("could not read body fixture %s: %v", name, err)
+	}
+	return string(b)
+}
+
+func TestParseBodyLinks(t *testing.T) {
+	tests := map[string]struct {
+		body          string
+		expectedLinks []string
+	}{
+		"article with links": {
+			body: readBodyFixture(t, "body-with-links.xml"),
+			expectedLinks: []string{
+				"0e3a1c3e-d1b6-11e8-a9f2-7574db66bcd5",
+				"5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4",
+			},
+		},
+		"no body": {
+			body:          "",
+			expectedLinks: []string{},
+		},
+		"body without links": {
+			body:          "<body><p>Some text</p></body>",
+			expectedLinks: []string{},
+		},
+		"link to itself": {
+			body:          `<body><a href="https://www.ft.com/content/` + bodyTestUUID + `">this article</a></body>`,
+			expectedLinks: []string{},
+		},
+		"api link": {
+			body:          `<body><a href="https://api.ft.com/content/5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4">api</a></body>`,
+			expectedLinks: []string{"5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4"},
+		},
+		"not a content url": {
+			body:          `<body><a href="https://www.ft.com/content/5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4/comments/extra">x</a></body>`,
+			expectedLinks: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := parseBody(bodyTestUUID, test.body)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedLinks, data.links)
+		})
+	}
+}
+
+func TestParseMalformedBody(t *testing.T) {
+	tests := map[string]string{
+		"truncated article":  readBodyFixture(t, "body-truncated.xml"),
+		"unclosed element":   `<body><p><a href="https://www.ft.com/content/5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4">link</a>`,
+		"mismatched element": `<body><p><a href="https://www.ft.com/content/5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4">link</a></b></body>`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := parseBody(bodyTestUUID, body)
+
+			assert.Error(t, err)
+			assert.Equal(t, newBodyData(), data, "nothing should be derived from a malformed body")
+		})
+	}
+}
+
+func TestParseBodyEmbeds(t *testing.T) {
+	tests := map[string]struct {
+		body           string
+		expectedEmbeds []embed
+		expectedLinks  []string
+	}{
+		"article with embedded media and a layout": {
+			body: readBodyFixture(t, "body-with-embeds.xml"),
+			expectedEmbeds: []embed{
+				{UUID: "a1b2c3d4-0f1e-4d2c-8b3a-5e6f7a8b9c0d", Position: 0},
+				{UUID: "3f2e1d0c-9b8a-4765-a4b3-c2d1e0f9a8b7", Position: 1},
+				{UUID: "7c6b5a49-3827-4165-9e0d-fc1b2a3d4e5f", Position: 2},
+			},
+			expectedLinks: []string{"5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4"},
+		},
+		"article with links and an image": {
+			body: readBodyFixture(t, "body-with-links.xml"),
+			expectedEmbeds: []embed{
+				{UUID: "2bc6a3c8-d1bd-11e8-a9f2-7574db66bcd5", Position: 0},
+			},
+			expectedLinks: []string{
+				"0e3a1c3e-d1b6-11e8-a9f2-7574db66bcd5",
+				"5d1f0a4e-d170-11e8-9a3c-5d5eac8f1ab4",
+			},
+		},
+		"no body": {
+			body:           "",
+			expectedEmbeds: []embed{},
+			expectedLinks:  []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := parseBody(bodyTestUUID, test.body)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedEmbeds, data.embeds)
+			assert.Equal(t, test.expectedLinks, data.links)
+		})
+	}
+}
+
+func TestEmbedEntries(t *testing.T) {
+	data := bodyData{embeds: []embed{
+		{UUID: "a1b2c3d4-0f1e-4d2c-8b3a-5e6f7a8b9c0d", Position: 0},
+		{UUID: "3f2e1d0c-9b8a-4765-a4b3-c2d1e0f9a8b7", Position: 1},
+	}}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"uuid": "a1b2c3d4-0f1e-4d2c-8b3a-5e6f7a8b9c0d", "position": 0},
+		{"uuid": "3f2e1d0c-9b8a-4765-a4b3-c2d1e0f9a8b7", "position": 1},
+	}, data.embedEntries())
+	assert.Equal(t, []map[string]interface{}{}, newBodyData().embedEntries())
+}
+
+func TestParseBodyMetrics(t *testing.T) {
+	tests := map[string]struct {
+		body            string
+		expectedMetrics *bodyMetrics
+	}{
+		"article with links": {
+			body:            readBodyFixture(t, "body-with-links.xml"),
+			expectedMetrics: &bodyMetrics{WordCount: 461, ParagraphCount: 13, ReadingTimeMinutes: 3},
+		},
+		"article with embedded media": {
+			body:            readBodyFixture(t, "body-with-embeds.xml"),
+			expectedMetrics: &bodyMetrics{WordCount: 238, ParagraphCount: 8, ReadingTimeMinutes: 2},
+		},
+		"long read with headings, lists, pull quotes, a big number, a related box and an empty paragraph": {
+			body:            readBodyFixture(t, "body-long-read.xml"),
+			expectedMetrics: &bodyMetrics{WordCount: 848, ParagraphCount: 20, ReadingTimeMinutes: 5},
+		},
+		"words split by inline elements": {
+			body:            `<body><p>F<sup>1</sup> driver <em>Lewis</em> Hamilton</p></body>`,
+			expectedMetrics: &bodyMetrics{WordCount: 4, ParagraphCount: 1, ReadingTimeMinutes: 1},
+		},
+		"words split by line breaks and punctuation": {
+			body:            `<body><p>First line<br>second line — end</p><p>.</p></body>`,
+			expectedMetrics: &bodyMetrics{WordCount: 5, ParagraphCount: 1, ReadingTimeMinutes: 1},
+		},
+		"empty body element": {
+			body:            `<body></body>`,
+			expectedMetrics: &bodyMetrics{},
+		},
+		"no body": {
+			body:            "",
+			expectedMetrics: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := parseBody(bodyTestUUID, test.body)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedMetrics, data.metrics)
+		})
+	}
+}
+
+func TestReadingTimeIsRoundedUp(t *testing.T) {
+	tests := map[int]int{
+		0:   0,
+		1:   1,
+		200: 1,
+		201: 2,
+		950: 5,
+	}
+
+	for words, expectedMinutes := range tests {
+		assert.Equal(t, expectedMinutes, newBodyMetrics(words, 0).ReadingTimeMinutes, "reading time of %d words", words)
+	}
+}
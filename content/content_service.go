@@ -1,11 +1,12 @@
 package content
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/Financial-Times/go-logger/v2"
 
@@ -30,25 +31,34 @@ var contentTypesWithNoBody = map[string]bool{
 }
 
 type Service struct {
-	driver *cmneo4j.Driver
-	agent  policy.Agent
-	log    *logger.UPPLogger
+	driver    *cmneo4j.Driver
+	agent     policy.Agent
+	batchSize int
+	log       *logger.UPPLogger
 }
 
 // NewCypherDriver instantiate driver
-func NewContentService(d *cmneo4j.Driver, a policy.Agent, l *logger.UPPLogger) Service {
+func NewContentService(d *cmneo4j.Driver, a policy.Agent, batchSize int, l *logger.UPPLogger) Service {
 	return Service{
-		driver: d,
-		agent:  a,
-		log:    l,
+		driver:    d,
+		agent:     a,
+		batchSize: batchSize,
+		log:       l,
 	}
 }
 
-// Initialise ensures constraints on content uuid
+// titleSearchIndex is the full-text index of the content titles used by Search
+const titleSearchIndex = "content_title"
+
+// Initialise ensures constraints on content uuid, editorial desk path and the schema migration nodes.
+// The indexes are created by MigrateSchema.
 func (cd Service) Initialise() error {
-	err := cd.driver.EnsureConstraints(map[string]string{
-		"Content": "uuid"})
-	return err
+	return cd.driver.EnsureConstraints(map[string]string{
+		"Content":                   "uuid",
+		"EditorialDesk":             "path",
+		"SchemaMigration":           "version",
+		"SchemaMigrationLock":       "name",
+		"PlaceholderCollectionLock": "name"})
 }
 
 // Check - Feeds into the Healthcheck and checks whether we can connect to Neo and that the datastore isn't empty and
@@ -57,23 +67,31 @@ func (cd Service) Check() error {
 	return cd.driver.VerifyConnectivity()
 }
 
+// ReadOptions are the optional parts of the content returned by ReadWithOptions
+type ReadOptions struct {
+	// IncludeEmbeds returns the UUIDs of the content embedded in the body, in the order they are embedded
+	IncludeEmbeds bool
+}
+
 // Read - reads a content given a UUID
 func (cd Service) Read(uuid string, transID string) (interface{}, bool, error) {
-	var results []struct {
-		content
+	return cd.ReadWithOptions(uuid, transID, ReadOptions{})
+}
+
+// ReadWithOptions - reads a content given a UUID, along with the optional parts requested in the options
+func (cd Service) ReadWithOptions(uuid string, transID string, opts ReadOptions) (interface{}, bool, error) {
+	result, found, err := cd.readContent(uuid, opts)
+	if err != nil || !found {
+		return content{}, found, err
 	}
+	return result.toContent(), true, nil
+}
+
+func (cd Service) readContent(uuid string, opts ReadOptions) (readResult, bool, error) {
+	var results []readResult
 
 	query := &cmneo4j.Query{
-		Cypher: `MATCH (n:Content {uuid: $uuid})
-			OPTIONAL MATCH (sp:Thing)-[rel1:IS_CURATED_FOR]->(n)
-			OPTIONAL MATCH (n)-[rel2:CONTAINS]->(cp:Thing)
-			WITH n,sp,cp
-			RETURN n.uuid as uuid,
-				n.title as title,
-				n.publishedDate as publishedDate,
-				n.publication as publication,
-				sp.uuid as storyPackage,
-				cp.uuid as contentPackage`,
+		Cypher: `MATCH (n:Content {uuid: $uuid})` + readContentCypher(opts),
 		Params: map[string]interface{}{
 			"uuid": uuid,
 		},
@@ -83,33 +101,380 @@ func (cd Service) Read(uuid string, transID string) (interface{}, bool, error) {
 	err := cd.driver.Read(query)
 
 	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
-		return content{}, false, nil
+		return readResult{}, false, nil
+	}
+
+	if err != nil {
+		return readResult{}, false, err
+	}
+
+	return results[0], true, nil
+}
+
+// ReadBatch - reads the content with the given UUIDs with a single query. The content found is returned
+// in the order of the UUIDs, along with the UUIDs of the missing content.
+func (cd Service) ReadBatch(uuids []string, transID string, opts ReadOptions) (batchReadResult, error) {
+	batch := batchReadResult{Content: []content{}, Missing: []string{}}
+	uuids = mergeUUIDs("", uuids)
+	if len(uuids) == 0 {
+		return batch, nil
+	}
+
+	var results []readResult
+
+	query := &cmneo4j.Query{
+		Cypher: `UNWIND $uuids AS uuid
+			MATCH (n:Content {uuid: uuid})` + readContentCypher(opts),
+		Params: map[string]interface{}{
+			"uuids": uuids,
+		},
+		Result: &results,
+	}
+
+	err := cd.driver.Read(query)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return batchReadResult{}, err
+	}
+
+	found := make(map[string]content, len(results))
+	for _, result := range results {
+		found[result.UUID] = result.toContent()
+	}
+
+	for _, uuid := range uuids {
+		if c, ok := found[uuid]; ok {
+			batch.Content = append(batch.Content, c)
+		} else {
+			batch.Missing = append(batch.Missing, uuid)
+		}
+	}
+	return batch, nil
+}
+
+// List - reads a page of the content selected by the filter, each content being returned as by Read.
+// Content is listed by UUID, the cursor being the NextCursor of the previous page or empty for the first page.
+// The limit must be between 1 and maxPageSize.
+func (cd Service) List(filter contentFilter, cursor string, limit int, transID string) (contentPage, error) {
+	if err := filter.validate(); err != nil {
+		return contentPage{}, err
 	}
 
+	if limit < 1 || limit > maxPageSize {
+		return contentPage{}, newValidationError(FieldError{
+			Field:   "limit",
+			Message: fmt.Sprintf("limit must be between 1 and %d", maxPageSize),
+		})
+	}
+
+	after, err := decodeCursor(cursor)
 	if err != nil {
-		return content{}, false, err
+		return contentPage{}, err
+	}
+
+	matchCypher, params := filter.matchCypher()
+	params["after"] = after
+	// one more item than requested tells whether there is a next page
+	params["limit"] = limit + 1
+
+	var results []readResult
+
+	query := &cmneo4j.Query{
+		Cypher: matchCypher + `
+			WITH n
+			WHERE n.uuid > $after
+			WITH n
+			ORDER BY n.uuid
+			LIMIT $limit` + readContentCypher(ReadOptions{}),
+		Params: params,
+		Result: &results,
+	}
+
+	err = cd.driver.Read(query)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return contentPage{}, err
 	}
 
-	result := results[0]
+	// the projection does not keep the order of the nodes
+	sort.Slice(results, func(i, j int) bool { return results[i].UUID < results[j].UUID })
 
+	page := contentPage{Content: []content{}}
+	for i, result := range results {
+		if i == limit {
+			page.NextCursor = encodeCursor(page.Content[limit-1].UUID)
+			break
+		}
+		page.Content = append(page.Content, result.toContent())
+	}
+	return page, nil
+}
+
+// encodeCursor returns the opaque cursor of the page following the content with the given UUID
+func encodeCursor(uuid string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(uuid))
+}
+
+// decodeCursor returns the UUID the page of the cursor follows, which is empty for the first page
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", newValidationError(FieldError{Field: "cursor", Message: fmt.Sprintf("%q is not a valid cursor", cursor)})
+	}
+	return string(b), nil
+}
+
+// readContentCypher projects the content node n as read by Read, along with the optional parts
+// requested in the options
+func readContentCypher(opts ReadOptions) string {
+	embedsCypher := `
+			WITH n, storyPackages, contentPackages, editorialDesk, publications, liveBlogPackage, null AS embeds`
+	if opts.IncludeEmbeds {
+		embedsCypher = `
+			OPTIONAL MATCH (n)-[e:EMBEDS]->(embedded:Thing)
+			WITH n, storyPackages, contentPackages, editorialDesk, publications, liveBlogPackage, embedded
+			ORDER BY e.position
+			WITH n, storyPackages, contentPackages, editorialDesk, publications, liveBlogPackage, collect(embedded.uuid) AS embeds`
+	}
+
+	// n.publication is only set on nodes written before publications were stored as relationships
+	// which have not been migrated yet, see MigratePublications.
+	// Publications are returned in the order they were written, the position of the relationship being
+	// missing on the ones written before it was stored, which come last by UUID.
+	return `
+			OPTIONAL MATCH (sp:Thing)-[:IS_CURATED_FOR]->(n)
+			WITH n, collect(DISTINCT sp.uuid) AS storyPackages
+			OPTIONAL MATCH (n)-[:CONTAINS]->(cp:Thing)
+			WITH n, storyPackages, collect(DISTINCT cp.uuid) AS contentPackages
+			OPTIONAL MATCH (n)-[:PUBLISHED_BY_DESK]->(d:EditorialDesk)
+			WITH n, storyPackages, contentPackages, head(collect(d.path)) AS editorialDesk
+			OPTIONAL MATCH (n)-[pi:PUBLISHED_IN]->(p:Thing)
+			WITH n, storyPackages, contentPackages, editorialDesk, p
+			ORDER BY pi.position, p.uuid
+			WITH n, storyPackages, contentPackages, editorialDesk, collect(DISTINCT p.uuid) AS publications
+			OPTIONAL MATCH (n)-[:IS_POST_OF]->(lb:Thing)
+			WITH n, storyPackages, contentPackages, editorialDesk, publications, head(collect(lb.uuid)) AS liveBlogPackage` +
+		embedsCypher + `
+			RETURN n.uuid as uuid,
+				n.title as title,
+				n.publishedDate as publishedDate,
+				n.firstPublishedDate as firstPublishedDate,
+				CASE WHEN size(publications) > 0 THEN publications ELSE n.publication END as publication,
+				` + typeCypher("n") + ` as type,
+				labels(n) as labels,
+				n.wordCount as wordCount,
+				n.paragraphCount as paragraphCount,
+				n.readingTimeMinutes as readingTimeMinutes,
+				editorialDesk,
+				liveBlogPackage,
+				storyPackages,
+				contentPackages,
+				embeds,
+				coalesce(n.version, 0) as version`
+}
+
+// readResult is a row returned by readContentCypher
+type readResult struct {
+	content
+	// Version is the number of writes of the content since versions were introduced, see writeContentQuery
+	Version int64 `json:"version"`
+}
+
+func (result readResult) toContent() content {
 	contentItem := content{
-		UUID:           result.UUID,
-		Title:          result.Title,
-		PublishedDate:  result.PublishedDate,
-		Publication:    result.Publication,
-		StoryPackage:   result.StoryPackage,
-		ContentPackage: result.ContentPackage,
+		UUID:               result.UUID,
+		Title:              result.Title,
+		PublishedDate:      result.PublishedDate,
+		FirstPublishedDate: result.FirstPublishedDate,
+		Publication:        result.Publication,
+		Type:               result.Type,
+		Labels:             sortedOrNil(result.Labels),
+		EditorialDesk:      result.EditorialDesk,
+		LiveBlogPackage:    result.LiveBlogPackage,
+		StoryPackages:      sortedOrNil(result.StoryPackages),
+		ContentPackages:    sortedOrNil(result.ContentPackages),
+		Embeds:             result.Embeds,
+		bodyMetrics:        result.bodyMetrics,
+	}
+	// the single valued fields are still populated for the clients which do not read the lists yet
+	if len(contentItem.StoryPackages) > 0 {
+		contentItem.StoryPackage = contentItem.StoryPackages[0]
+	}
+	if len(contentItem.ContentPackages) > 0 {
+		contentItem.ContentPackage = contentItem.ContentPackages[0]
+	}
+	return contentItem
+}
+
+// Members - reads a page of the content linked to a package: the content it contains or curates, the content
+// of the collection a content package contains and, for live blog packages, their posts. Members are ordered
+// from the most recently published.
+func (cd Service) Members(uuid string, offset int, limit int) (membersPage, bool, error) {
+	var results []struct {
+		Total   int      `json:"total"`
+		Members []member `json:"members"`
+	}
+
+	query := &cmneo4j.Query{
+		Cypher: `MATCH (p:Thing {uuid: $uuid})
+			OPTIONAL MATCH (p)-[:CONTAINS|IS_CURATED_FOR]->(contained:Content)
+			WITH p, collect(DISTINCT contained) AS contained
+			OPTIONAL MATCH (p)-[:CONTAINS]->(:Thing)-[:CONTAINS]->(packaged:Content)
+			WHERE p:ContentPackage
+			WITH p, contained, collect(DISTINCT packaged) AS packaged
+			OPTIONAL MATCH (post:Content)-[:IS_POST_OF]->(p)
+			WITH contained, packaged, collect(DISTINCT post) AS posts
+			WITH reduce(ms = [], m IN contained + packaged + posts | CASE WHEN m IN ms THEN ms ELSE ms + m END) AS allMembers
+			CALL {
+				WITH allMembers
+				UNWIND allMembers AS m
+				WITH m
+				ORDER BY m.publishedDateEpoch IS NULL, m.publishedDateEpoch DESC, m.uuid
+				SKIP $offset
+				LIMIT $limit
+				RETURN collect({
+					uuid: m.uuid,
+					type: ` + typeCypher("m") + `,
+					title: m.title,
+					publishedDate: m.publishedDate
+				}) AS members
+			}
+			RETURN size(allMembers) AS total, members`,
+		Params: map[string]interface{}{
+			"uuid":   uuid,
+			"offset": offset,
+			"limit":  limit,
+		},
+		Result: &results,
 	}
-	return contentItem, true, nil
+
+	err := cd.driver.Read(query)
+
+	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return membersPage{}, false, nil
+	}
+
+	if err != nil {
+		return membersPage{}, false, err
+	}
+
+	page := membersPage{
+		UUID:    uuid,
+		Total:   results[0].Total,
+		Offset:  offset,
+		Limit:   limit,
+		Members: results[0].Members,
+	}
+	if page.Members == nil {
+		page.Members = []member{}
+	}
+	return page, true, nil
 }
 
 // Write - Writes a content node
 func (cd Service) Write(thing interface{}, transID string) error {
 	c := thing.(content)
 
+	item, ok, err := cd.writeItem(c, nil)
+	if err != nil || !ok {
+		return err
+	}
+
+	return cd.driver.Write(writeContentQuery(getContentLabels(c), []interface{}{item}))
+}
+
+// WriteBatch - Writes several content nodes. Items sharing the same labels are written together
+// by a single statement, with at most batchSize items per statement and transaction.
+// Every item is validated before anything is written, the fields of the returned ValidationError
+// being prefixed by the index of their item, e.g. content[2].uuid.
+func (cd Service) WriteBatch(things []interface{}, transID string) error {
+	var labelsInOrder []string
+	itemsByLabels := map[string][]interface{}{}
+
+	var errs []FieldError
+	for i, thing := range things {
+		c := thing.(content)
+
+		item, ok, err := cd.writeItem(c, nil)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			errs = append(errs, validationErr.inItem(fmt.Sprintf("content[%d]", i)).Errors...)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		labels := getContentLabels(c)
+		if _, found := itemsByLabels[labels]; !found {
+			labelsInOrder = append(labelsInOrder, labels)
+		}
+		itemsByLabels[labels] = append(itemsByLabels[labels], item)
+	}
+	if len(errs) > 0 {
+		return newValidationError(errs...)
+	}
+
+	for _, labels := range labelsInOrder {
+		items := itemsByLabels[labels]
+
+		size := cd.batchSize
+		if size < 1 {
+			size = len(items)
+		}
+
+		for start := 0; start < len(items); start += size {
+			end := start + size
+			if end > len(items) {
+				end = len(items)
+			}
+
+			err := cd.driver.Write(writeContentQuery(labels, items[start:end]))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeItem decides whether the content should be persisted and, if so, builds its entry
+// in the $items parameter of writeContentQuery.
+// stored is the content already stored when patching it, otherwise nil. Stored content was eligible
+// when it was first written, so it stays eligible without a body unless the patch changes its type,
+// and when the patch has no body the data derived from the stored body is kept.
+func (cd Service) writeItem(c content, stored *content) (map[string]interface{}, bool, error) {
 	// Letting through only articles (which have body), live blogs, content packages, graphics, videos and audios (which don't have a body)
-	if c.Body == "" && !contentTypesWithNoBody[c.Type] {
-		return nil
+	if (stored == nil || c.Type != stored.Type) && c.Body == "" && !contentTypesWithNoBody[c.Type] {
+		return nil, false, nil
+	}
+
+	// only eligible content is validated, the other content being skipped, before evaluating the policy
+	if err := c.validate(); err != nil {
+		return nil, false, err
+	}
+
+	var publishedDate string
+	var publishedDateEpoch int64
+	if c.PublishedDate != "" {
+		var err error
+		publishedDate, publishedDateEpoch, err = normaliseDate("publishedDate", c.PublishedDate)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var firstPublished map[string]interface{}
+	if c.FirstPublishedDate != "" {
+		firstPublishedDate, firstPublishedDateEpoch, err := normaliseDate("firstPublishedDate", c.FirstPublishedDate)
+		if err != nil {
+			return nil, false, err
+		}
+		firstPublished = map[string]interface{}{
+			"date":  firstPublishedDate,
+			"epoch": firstPublishedDateEpoch,
+		}
 	}
 
 	result, err := cd.agent.EvaluateSpecialContentPolicy(
@@ -118,105 +483,126 @@ func (cd Service) Write(thing interface{}, transID string) error {
 		},
 	)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	if result.IsSpecialContent {
 		cd.log.Infof("Content with ID %s was marked as special content, it would not be persisted.", c.UUID)
-		return nil
+		return nil, false, nil
 	}
 
 	params := map[string]interface{}{
 		"uuid": c.UUID,
 	}
 
+	if c.Type != "" {
+		params["type"] = c.Type
+	}
+
 	if c.Title != "" {
 		params["title"] = c.Title
 		params["prefLabel"] = c.Title
 	}
 
 	if c.PublishedDate != "" {
-		params["publishedDate"] = c.PublishedDate
-		datetimeEpoch, err := time.Parse(time.RFC3339, c.PublishedDate)
-
-		if err != nil {
-			return err
-		}
-
-		params["publishedDateEpoch"] = datetimeEpoch.Unix()
+		params["publishedDate"] = publishedDate
+		params["publishedDateEpoch"] = publishedDateEpoch
 	}
 
-	if len(c.Publication) != 0 {
-		params["publication"] = c.Publication
+	item := map[string]interface{}{
+		"uuid":             c.UUID,
+		"props":            params,
+		"firstPublished":   firstPublished,
+		"storyPackages":    c.allStoryPackages(),
+		"contentPackages":  c.allContentPackages(),
+		"editorialDesks":   c.editorialDesks(),
+		"publications":     positionEntries(mergeUUIDs("", c.Publication)),
+		"liveBlogPackages": mergeUUIDs(c.LiveBlogPackage, nil),
 	}
 
-	deleteEntityRelationshipsQuery := &cmneo4j.Query{
-		Cypher: `MATCH (t:Thing {uuid: $uuid})
-				OPTIONAL MATCH (c:Thing)-[rel1:IS_CURATED_FOR]->(t)
-				OPTIONAL MATCH (cp:Thing)<-[rel2:CONTAINS]-(t)
-				DELETE rel1, rel2`,
-		Params: map[string]interface{}{
-			"uuid": c.UUID,
-		},
+	if stored != nil && c.Body == "" {
+		keepBodyData(item)
+		return item, true, nil
 	}
 
-	queries := []*cmneo4j.Query{deleteEntityRelationshipsQuery}
-
-	labels := getContentLabels(c)
-
-	if c.StoryPackage != "" {
-		addStoryPackageRelationQuery := addStoryPackageRelationQuery(c.UUID, c.StoryPackage)
-		queries = append(queries, addStoryPackageRelationQuery)
+	body, err := parseBody(c.UUID, c.Body)
+	if err != nil {
+		cd.log.WithUUID(c.UUID).WithError(err).Warn("Content body could not be parsed, the data derived from the previous body is kept")
+		keepBodyData(item)
+		return item, true, nil
 	}
 
-	if c.ContentPackage != "" {
-		addContentPackageRelationQuery := addContentPackageRelationQuery(c.UUID, c.ContentPackage)
-		queries = append(queries, addContentPackageRelationQuery)
+	if body.metrics != nil {
+		setBodyMetrics(params, *body.metrics)
 	}
+	item["links"] = body.links
+	item["embeds"] = body.embedEntries()
 
-	query := fmt.Sprintf(`MERGE (n:Thing {uuid: $uuid})
-		      set n=$allprops
-		      set n %s`, labels)
-
-	writeContentQuery := &cmneo4j.Query{
-		Cypher: query,
-		Params: map[string]interface{}{
-			"uuid":     c.UUID,
-			"allprops": params,
-		},
-	}
-
-	queries = append(queries, writeContentQuery)
-	err = cd.driver.Write(queries...)
-	if err != nil {
-		return err
-	}
-	return nil
+	return item, true, nil
 }
 
-func addStoryPackageRelationQuery(articleUUID, packageUUID string) *cmneo4j.Query {
-	query := `MERGE(sp:Thing{uuid:$packageUuid})
-			MERGE(c:Thing{uuid:$contentUuid})
-			MERGE(c)<-[rel:IS_CURATED_FOR]-(sp)`
+// keepBodyData makes writeContentQuery keep the relationships and metrics derived from the body last written
+func keepBodyData(item map[string]interface{}) {
+	// null links and embeds keep the existing relationships, see managedRelationship
+	item["links"] = nil
+	item["embeds"] = nil
+	item["keepBodyMetrics"] = true
+}
 
-	return &cmneo4j.Query{
-		Cypher: query,
-		Params: map[string]interface{}{
-			"packageUuid": packageUUID,
-			"contentUuid": articleUUID,
-		},
-	}
+func setBodyMetrics(params map[string]interface{}, metrics bodyMetrics) {
+	params["wordCount"] = metrics.WordCount
+	params["paragraphCount"] = metrics.ParagraphCount
+	params["readingTimeMinutes"] = metrics.ReadingTimeMinutes
 }
 
-func addContentPackageRelationQuery(articleUUID, packageUUID string) *cmneo4j.Query {
-	query := `MERGE(cp:Thing{uuid:$packageUuid})
-			MERGE(c:Thing{uuid:$contentUuid})
-			MERGE(c)-[rel:CONTAINS]->(cp)`
+// writeContentQuery upserts every item with the given labels and reconciles its managed relationships
+// in a single statement. publishedDateTime is the native datetime of publishedDate, so that it is
+// converted the same way as by MigratePublishedDateTime.
+// firstPublishedDate is the one of the item when set, otherwise the earliest of the stored one and publishedDate,
+// the stored publishedDate standing for the stored first published date of content written before it was introduced.
+// The stored body metrics are kept when the item is flagged with keepBodyMetrics, see keepBodyData.
+// Every write increments the version of the content, and an item with an expectedVersion is only written
+// when it is still the version of the stored content.
+func writeContentQuery(labels string, items []interface{}) *cmneo4j.Query {
+	// setting the uuid write locks the node before its properties are read, so that concurrent writes
+	// are serialised and the version is compared and incremented from the last committed one
+	query := fmt.Sprintf(`UNWIND $items AS item
+		OPTIONAL MATCH (stored:Thing {uuid: item.uuid})
+		WHERE item.expectedVersion IS NOT NULL
+		SET stored.uuid = item.uuid
+		WITH item, stored
+		WHERE item.expectedVersion IS NULL OR (stored IS NOT NULL AND coalesce(stored.version, 0) = item.expectedVersion)
+		MERGE (n:Thing {uuid: item.uuid})
+		SET n.uuid = item.uuid
+		WITH n, item, n.version AS storedVersion, CASE
+			WHEN n.firstPublishedDateEpoch IS NOT NULL
+				THEN {date: n.firstPublishedDate, epoch: n.firstPublishedDateEpoch}
+			WHEN n.publishedDateEpoch IS NOT NULL
+				THEN {date: n.publishedDate, epoch: n.publishedDateEpoch}
+		END AS storedFirstPublished
+		WITH n, item, storedVersion, CASE
+			WHEN item.firstPublished IS NOT NULL
+				THEN item.firstPublished
+			WHEN storedFirstPublished IS NOT NULL AND
+				(item.props.publishedDateEpoch IS NULL OR storedFirstPublished.epoch <= item.props.publishedDateEpoch)
+				THEN storedFirstPublished
+			WHEN item.props.publishedDateEpoch IS NOT NULL
+				THEN {date: item.props.publishedDate, epoch: item.props.publishedDateEpoch}
+		END AS firstPublished, CASE
+			WHEN item.keepBodyMetrics
+				THEN {wordCount: n.wordCount, paragraphCount: n.paragraphCount, readingTimeMinutes: n.readingTimeMinutes}
+			ELSE {}
+		END AS keptBodyMetrics
+		SET n = item.props
+		SET n += keptBodyMetrics
+		SET n.version = coalesce(storedVersion, 0) + 1
+		SET n.firstPublishedDate = firstPublished.date, n.firstPublishedDateEpoch = firstPublished.epoch
+		SET n.publishedDateTime = datetime(n.publishedDate)
+		SET n %s`, labels) + relationshipsCypher()
 
 	return &cmneo4j.Query{
 		Cypher: query,
 		Params: map[string]interface{}{
-			"packageUuid": packageUUID,
-			"contentUuid": articleUUID,
+			"items": items,
 		},
 	}
 }
@@ -275,6 +661,31 @@ func (cd Service) Delete(uuid string, transID string) (bool, error) {
 	return s1.Counters().NodesDeleted() > 0, nil
 }
 
+// DeleteBatch - deletes the content items with the given UUIDs one after the other, each of them as by Delete.
+// A failure to delete an item, including a malformed UUID, does not stop the deletion of the others, the outcome
+// of each item being returned in the order of the UUIDs.
+func (cd Service) DeleteBatch(uuids []string, transID string) []deleteOutcome {
+	outcomes := []deleteOutcome{}
+	for _, uuid := range mergeUUIDs("", uuids) {
+		if !uuidRegex.MatchString(uuid) {
+			outcomes = append(outcomes, deleteOutcome{UUID: uuid, Status: deleteFailed, Error: fmt.Sprintf("%q is not a valid UUID", uuid)})
+			continue
+		}
+
+		deleted, err := cd.Delete(uuid, transID)
+		switch {
+		case err != nil:
+			cd.log.WithTransactionID(transID).WithUUID(uuid).WithError(err).Error("Failed to delete content")
+			outcomes = append(outcomes, deleteOutcome{UUID: uuid, Status: deleteFailed, Error: err.Error()})
+		case deleted:
+			outcomes = append(outcomes, deleteOutcome{UUID: uuid, Status: deleteDeleted})
+		default:
+			outcomes = append(outcomes, deleteOutcome{UUID: uuid, Status: deleteNotFound})
+		}
+	}
+	return outcomes
+}
+
 // DecodeJSON - Decodes JSON into content
 func (cd Service) DecodeJSON(dec *json.Decoder) (interface{}, string, error) {
 	c := content{}
@@ -284,12 +695,24 @@ func (cd Service) DecodeJSON(dec *json.Decoder) (interface{}, string, error) {
 
 // Count - Returns a count of the number of content items in this Neo instance
 func (cd Service) Count() (int, error) {
+	return cd.CountFiltered(contentFilter{})
+}
+
+// CountFiltered - Returns the number of content items selected by the filter
+func (cd Service) CountFiltered(filter contentFilter) (int, error) {
+	if err := filter.validate(); err != nil {
+		return 0, err
+	}
+
 	var results []struct {
 		Count int `json:"c"`
 	}
 
+	matchCypher, params := filter.matchCypher()
 	query := &cmneo4j.Query{
-		Cypher: `MATCH (n:Content) return count(n) as c`,
+		Cypher: matchCypher + `
+			RETURN count(n) as c`,
+		Params: params,
 		Result: &results,
 	}
 
@@ -305,6 +728,69 @@ func (cd Service) Count() (int, error) {
 	return results[0].Count, nil
 }
 
+// countGroupings are the Cypher computing the keys content is grouped by in a count breakdown,
+// content being counted in every group it has a key of:
+//   - type: the labels getContentLabels assigns besides Content, which is the key of content without any other label
+//   - publication: the UUIDs of the publications, including the ones not migrated yet, see MigratePublications
+//   - month: the UTC month of publishedDate, e.g. 2014-07
+var countGroupings = map[string]string{
+	"type": `
+			WITH n, [label IN labels(n) WHERE NOT label IN ['Thing', 'Content']] AS typeLabels
+			UNWIND CASE WHEN size(typeLabels) = 0 THEN ['Content'] ELSE typeLabels END AS key`,
+	"publication": `
+			OPTIONAL MATCH (n)-[:PUBLISHED_IN]->(p:Thing)
+			WITH n, collect(DISTINCT p.uuid) AS publications
+			UNWIND CASE
+				WHEN size(publications) > 0 THEN publications
+				WHEN size(coalesce(n.publication, [])) > 0 THEN n.publication
+				ELSE [null]
+			END AS key`,
+	"month": `
+			WITH n, CASE
+				WHEN n.publishedDateEpoch IS NOT NULL
+					THEN substring(toString(datetime({epochSeconds: n.publishedDateEpoch})), 0, 7)
+			END AS key`,
+}
+
+// CountBy - Returns the number of content items selected by the filter broken down by type, publication or month.
+// Content without any key, e.g. without publishedDate when grouped by month, is counted in the group with a null key.
+func (cd Service) CountBy(groupBy string, filter contentFilter) (countBreakdown, error) {
+	groupingCypher, found := countGroupings[groupBy]
+	if !found {
+		return countBreakdown{}, newValidationError(FieldError{
+			Field:   "groupBy",
+			Message: fmt.Sprintf("%q is not a valid grouping, expected type, publication or month", groupBy),
+		})
+	}
+
+	total, err := cd.CountFiltered(filter)
+	if err != nil {
+		return countBreakdown{}, err
+	}
+
+	var results []countGroup
+
+	matchCypher, params := filter.matchCypher()
+	query := &cmneo4j.Query{
+		Cypher: matchCypher + groupingCypher + `
+			RETURN key, count(DISTINCT n) AS count
+			ORDER BY key`,
+		Params: params,
+		Result: &results,
+	}
+
+	err = cd.driver.Read(query)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return countBreakdown{}, err
+	}
+
+	breakdown := countBreakdown{GroupBy: groupBy, Total: total, Groups: results}
+	if breakdown.Groups == nil {
+		breakdown.Groups = []countGroup{}
+	}
+	return breakdown, nil
+}
+
 func getContentLabels(c content) string {
 	specialTypes := map[string]bool{
 		"Content":        true,
@@ -320,7 +806,7 @@ func getContentLabels(c content) string {
 		labels = append(labels, c.Type)
 	}
 
-	if c.ContentPackage != "" {
+	if len(c.allContentPackages()) > 0 {
 		labels = append(labels, "ContentPackage")
 		if c.Type == LiveBlogPackage {
 			labels = append(labels, LiveBlogPackage)
@@ -328,3 +814,34 @@ func getContentLabels(c content) string {
 	}
 	return ":" + strings.Join(labels, ":")
 }
+
+// typeCypher returns the Cypher expression of the type of the given content node. The type is only stored
+// on content written since it is returned by Read, so for older content it is derived from the labels
+// added by getContentLabels, the type specific label being preferred to the package ones.
+// It is null when the labels do not tell the type, e.g. for content with the Content label only.
+func typeCypher(node string) string {
+	packageTypes := []string{LiveBlogPackage, "ContentPackage"}
+	var types []string
+	for t := range allowedTypes {
+		if t != "Content" && t != LiveBlogPackage && t != "ContentPackage" {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+
+	return fmt.Sprintf(`coalesce(%[1]s.type, head([l IN labels(%[1]s) WHERE l IN %[2]s]), head([l IN %[3]s WHERE l IN labels(%[1]s)]))`,
+		node, cypherStrings(types), cypherStrings(packageTypes))
+}
+
+// cypherStrings returns the Cypher list literal of the given strings, which must not hold quotes
+func cypherStrings(values []string) string {
+	return "['" + strings.Join(values, "', '") + "']"
+}
+
+func sortedOrNil(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package content
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+const republishStormSize = 500
+
+// republishStorm returns content items shaped like the ones sent when a large number of
+// articles is republished at once.
+func republishStorm() []content {
+	items := make([]content, 0, republishStormSize)
+	for i := 0; i < republishStormSize; i++ {
+		items = append(items, content{
+			UUID:          fmt.Sprintf("00000000-0000-4000-8000-%012d", i),
+			Title:         fmt.Sprintf("Republished article %d", i),
+			PublishedDate: "2024-03-01T10:00:00.000Z",
+			Body:          "<body><p>Some body</p></body>",
+			Type:          "Article",
+			StoryPackage:  storyPackageUUID,
+			EditorialDesk: "/FT/Standard Content",
+			Publication:   []string{"8e6c705e-1132-42a2-8db0-c295e29e8658"},
+		})
+	}
+	return items
+}
+
+func BenchmarkWrite(b *testing.B) {
+	asst := assert.New(b)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, b)
+	d := getDriverAndCheckClean(b, asst, l)
+	s := NewContentService(d, a, 1024, l)
+	asst.NoError(s.Initialise())
+	_, err := s.MigrateSchema(time.Minute)
+	asst.NoError(err)
+
+	items := republishStorm()
+	defer cleanBenchmarkDB(d, items, asst)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range items {
+			if err := s.Write(c, "TEST_TRANS_ID"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportMetric(float64(b.N*len(items))/b.Elapsed().Seconds(), "items/s")
+}
+
+func BenchmarkWriteBatch(b *testing.B) {
+	asst := assert.New(b)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, b)
+	d := getDriverAndCheckClean(b, asst, l)
+	s := NewContentService(d, a, 1024, l)
+	asst.NoError(s.Initialise())
+	_, err := s.MigrateSchema(time.Minute)
+	asst.NoError(err)
+
+	items := republishStorm()
+	defer cleanBenchmarkDB(d, items, asst)
+
+	batch := make([]interface{}, 0, len(items))
+	for _, c := range items {
+		batch = append(batch, c)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.WriteBatch(batch, "TEST_TRANS_ID"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(items))/b.Elapsed().Seconds(), "items/s")
+}
+
+func cleanBenchmarkDB(d *cmneo4j.Driver, items []content, a *assert.Assertions) {
+	uuids := make([]string, 0, len(items))
+	for _, c := range items {
+		uuids = append(uuids, c.UUID)
+	}
+
+	err := d.Write(&cmneo4j.Query{
+		Cypher: `MATCH (t:Thing) WHERE t.uuid IN $uuids DETACH DELETE t`,
+		Params: map[string]interface{}{
+			"uuids": uuids,
+		},
+	})
+	a.NoError(err)
+	cleanDB(d, a)
+}
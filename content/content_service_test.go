@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Financial-Times/opa-client-go"
 
@@ -32,6 +33,7 @@ const (
 	contentPlaceholderUUID       = "ed2d9fc2-b515-4f7d-8b4e-3b0c1fa90986"
 	videoContentUUID             = "41bb9444-e3cf-46d4-8182-6702844dc5c1"
 	storyPackageUUID             = "3b08c76c-7479-461d-9f0e-a4e92dca56f7"
+	otherStoryPackageUUID        = "9a3f4d6e-5c8b-4a7f-8e1d-2b6c0f4e7a19"
 	contentPackageUUID           = "45163790-eec9-11e6-abbc-ee7d9c5b3b90"
 	contentCollectionUUID        = "cc65c43a-fe4e-4315-854b-9b82435be036"
 	thingUUID                    = "ebcfe37d-9a70-4c8b-bf01-1feee4dff4b7"
@@ -39,6 +41,13 @@ const (
 	graphicUUID                  = "087b42c2-ac7f-40b9-b112-98b3a7f9cd72"
 	audioContentUUID             = "128cfcf4-c394-4e71-8c65-198a675acf53"
 	liveEventUUID                = "23531906-9f98-45c7-a9db-d05bdb72eeaf"
+	liveBlogPostUUID             = "5f8b1a7c-2d3e-4b6f-9a0c-7e1d4c2b8a63"
+	otherLiveBlogUUID            = "c0e4b2a1-7f3d-4e8a-b5c6-1d9e8f7a6b54"
+	publicationUUID              = "8e6c705e-1132-42a2-8db0-c295e29e8658"
+	otherPublicationUUID         = "88fdde6c-2aa4-4f78-af02-9f680097cfd6"
+	standardEditorialDesk        = "/FT/Standard Content"
+	otherEditorialDesk           = "/FT/Other Content"
+	testBatchSize                = 2
 	defaultPolicy                = `
 	package content_rw_neo4j.special_content
 	
@@ -104,8 +113,8 @@ var standardContent = content{
 	PublishedDate: "1970-01-01T01:00:00.000Z",
 	Body:          "Some body",
 	StoryPackage:  storyPackageUUID,
-	EditorialDesk: "/FT/Standard Content",
-	Publication:   []string{"8e6c705e-1132-42a2-8db0-c295e29e8658"},
+	EditorialDesk: standardEditorialDesk,
+	Publication:   []string{publicationUUID},
 }
 
 var standardContentPackage = content{
@@ -133,6 +142,24 @@ var liveBlogPackage = content{
 	Type:           "LiveBlogPackage",
 }
 
+var multiplePackagesContent = content{
+	UUID:            contentUUID,
+	Title:           "Content Title",
+	PublishedDate:   "1970-01-01T01:00:00.000Z",
+	Body:            "Some body",
+	StoryPackage:    storyPackageUUID,
+	StoryPackages:   []string{storyPackageUUID, otherStoryPackageUUID},
+	ContentPackages: []string{contentPackageUUID, genericContentPackageUUID},
+}
+
+var liveBlogPost = content{
+	UUID:            liveBlogPostUUID,
+	Title:           "Live blog post",
+	PublishedDate:   "1970-01-01T02:00:00.000Z",
+	Type:            "LiveBlogPost",
+	LiveBlogPackage: liveBlogUUID,
+}
+
 var liveEventContent = content{
 	UUID: liveEventUUID,
 	Type: "LiveEvent",
@@ -194,6 +221,10 @@ func TestGetContentLabels(t *testing.T) {
 			Content:  liveEventContent,
 			Expected: ":Content:LiveEvent",
 		},
+		"multiple Content Packages": {
+			Content:  multiplePackagesContent,
+			Expected: ":Content:ContentPackage",
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -361,38 +392,1546 @@ func TestDeleteContentPackageIsDeletedAttachedNodeIsAlsoDeleted(t *testing.T) {
 
 	existsThing, err := doesThingExist(thingUUID, d)
 	asst.NoError(err)
-	asst.False(existsThing, "Thing related to deleted Content Package should not exist")
+	asst.False(existsThing, "Thing related to deleted Content Package should not exist")
+}
+
+func TestDeleteBatchReportsTheOutcomeOfEveryUUID(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(genericContentPackage, "TEST_TRANS_ID"), "Failed to write content package")
+	writeNodeWithLabels(d, thingUUID, "Thing", asst)
+	writeContentPackageContainsRelation(d, genericContentPackage.UUID, thingUUID, asst)
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+
+	outcomes := s.DeleteBatch([]string{genericContentPackage.UUID, "not-a-uuid", otherLiveBlogUUID, liveBlogPostUUID, genericContentPackage.UUID}, "TEST_TRANS_ID")
+
+	asst.Equal([]deleteOutcome{
+		{UUID: genericContentPackage.UUID, Status: deleteDeleted},
+		{UUID: "not-a-uuid", Status: deleteFailed, Error: `"not-a-uuid" is not a valid UUID`},
+		{UUID: otherLiveBlogUUID, Status: deleteNotFound},
+		{UUID: liveBlogPostUUID, Status: deleteDeleted},
+	}, outcomes)
+
+	for _, uuid := range []string{genericContentPackage.UUID, thingUUID, liveBlogPostUUID} {
+		exists, err := doesThingExist(uuid, d)
+		asst.NoError(err)
+		asst.False(exists, "%s should have been deleted along with the content packages", uuid)
+	}
+}
+
+func TestCreateAllValuesPresent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	storedContent, _, err := s.Read(standardContent.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.NotEmpty(storedContent, "Failed to retrieve stored content")
+	actualContent := storedContent.(content)
+
+	asst.Equal(standardContent.UUID, actualContent.UUID, "Failed to match UUID")
+	asst.Equal(standardContent.Title, actualContent.Title, "Failed to match Title")
+	asst.Equal(
+		standardContent.PublishedDate,
+		actualContent.PublishedDate,
+		"Failed to match PublishedDate",
+	)
+	asst.Empty(actualContent.Body, "Body should not have been stored")
+	asst.Equal(standardContent.StoryPackage, actualContent.StoryPackage)
+	asst.Equal(standardContent.ContentPackage, actualContent.ContentPackage)
+	asst.Equal(standardContent.Publication, actualContent.Publication)
+	asst.Equal(standardContent.EditorialDesk, actualContent.EditorialDesk)
+}
+
+func TestCreateMultiplePackages(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(multiplePackagesContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	storedContent, found, err := s.Read(multiplePackagesContent.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.True(found, "Failed to retrieve stored content")
+	actualContent := storedContent.(content)
+
+	want := content{
+		UUID:            multiplePackagesContent.UUID,
+		Title:           multiplePackagesContent.Title,
+		PublishedDate:   multiplePackagesContent.PublishedDate,
+		StoryPackage:    storyPackageUUID,
+		StoryPackages:   []string{storyPackageUUID, otherStoryPackageUUID},
+		ContentPackage:  genericContentPackageUUID,
+		ContentPackages: []string{genericContentPackageUUID, contentPackageUUID},
+		Labels:          []string{"Content", "ContentPackage", "Thing"},
+	}
+	asst.Equal(want, actualContent)
+
+	asst.Equal(
+		1,
+		checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst),
+		"incorrect number of isCuratedFor relationships",
+	)
+	asst.Equal(
+		1,
+		checkContainsRelationship(d, genericContentPackageUUID, asst),
+		"incorrect number of contains relationships",
+	)
+
+	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to write updated content")
+
+	asst.Equal(
+		0,
+		checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst),
+		"relationship to the removed story package should have been deleted",
+	)
+	asst.Equal(
+		0,
+		checkContainsRelationship(d, genericContentPackageUUID, asst),
+		"relationship to the removed content package should have been deleted",
+	)
+}
+
+func TestReadReturnsTypeAndLabels(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	tests := map[string]struct {
+		content        content
+		expectedLabels []string
+	}{
+		"article": {
+			content:        liveBlog,
+			expectedLabels: []string{"Article", "Content", "Thing"},
+		},
+		"live blog package": {
+			content:        liveBlogPackage,
+			expectedLabels: []string{"Content", "ContentPackage", "LiveBlogPackage", "Thing"},
+		},
+		"placeholder": {
+			content:        contentPlaceholder,
+			expectedLabels: []string{"Content", "Thing"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asst.NoError(s.Write(test.content, "TEST_TRANS_ID"), "Failed to write content")
+
+			storedContent, found, err := s.Read(test.content.UUID, "TEST_TRANS_ID")
+			asst.NoError(err)
+			asst.True(found, "Failed to retrieve stored content")
+
+			asst.Equal(test.content.Type, storedContent.(content).Type, "Failed to match Type")
+			asst.Equal(test.expectedLabels, storedContent.(content).Labels, "Failed to match Labels")
+		})
+	}
+}
+
+func TestReadDerivesTypeOfContentWrittenWithoutIt(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	tests := map[string]struct {
+		labels       string
+		expectedType string
+	}{
+		"article": {
+			labels:       "Thing:Content:Article",
+			expectedType: "Article",
+		},
+		"article in a content package": {
+			labels:       "Thing:Content:Article:ContentPackage",
+			expectedType: "Article",
+		},
+		"live blog package": {
+			labels:       "Thing:Content:ContentPackage:LiveBlogPackage",
+			expectedType: LiveBlogPackage,
+		},
+		"content package": {
+			labels:       "Thing:Content:ContentPackage",
+			expectedType: "ContentPackage",
+		},
+		"unknown type": {
+			labels:       "Thing:Content:ContentCollection",
+			expectedType: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			writeNodeWithLabels(d, contentUUID, test.labels, asst)
+			defer cleanDB(d, asst)
+
+			storedContent, found, err := s.Read(contentUUID, "TEST_TRANS_ID")
+			asst.NoError(err)
+			asst.True(found, "Failed to retrieve stored content")
+			asst.Equal(test.expectedType, storedContent.(content).Type, "Failed to match Type")
+		})
+	}
+}
+
+func TestCreateNotAllValuesPresent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	storedContent, _, err := s.Read(shorterContent.UUID, "TEST_TRANS_ID")
+
+	asst.NoError(err)
+	asst.Empty(storedContent.(content).PublishedDate)
+	asst.Empty(storedContent.(content).Title)
+}
+
+func TestWillUpdateProperties(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+
+	asst.NoError(err)
+	asst.Equal(storedContent.(content).Title, standardContent.Title)
+	asst.Equal(storedContent.(content).PublishedDate, standardContent.PublishedDate)
+
+	asst.NoError(s.Write(updatedContent, "TEST_TRANS_ID"), "Failed to write updated content")
+	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+
+	asst.NoError(err)
+	asst.Equal(
+		storedContent.(content).Title,
+		updatedContent.Title,
+		"Should have updated Title but it is still present",
+	)
+	asst.Equal(
+		storedContent.(content).PublishedDate,
+		updatedContent.PublishedDate,
+		"Should have updated PublishedDate but it is still present",
+	)
+}
+
+func TestUpdateWillRemovePropertiesNoLongerPresent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to write content")
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+
+	asst.NoError(err)
+	asst.NotEmpty(storedContent.(content).Title)
+	asst.NotEmpty(storedContent.(content).PublishedDate)
+	asst.Equal(
+		1,
+		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
+		"incorrect number of isCuratedFor relationships",
+	)
+	asst.Equal(
+		1,
+		checkContainsRelationship(d, contentPackageUUID, asst),
+		"incorrect number of contains relationships",
+	)
+
+	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write updated content")
+	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+
+	asst.NoError(err)
+	asst.NotEmpty(storedContent, "Failed to rеtriеve updated content")
+	asst.Empty(
+		storedContent.(content).Title,
+		"Update should have removed Title but it is still present",
+	)
+	asst.Empty(
+		storedContent.(content).PublishedDate,
+		"Update should have removed PublishedDate but it is still present",
+	)
+	asst.Equal(
+		0,
+		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
+		"incorrect number of isCuratedFor relationships",
+	)
+	asst.Equal(
+		0,
+		checkContainsRelationship(d, contentPackageUUID, asst),
+		"incorrect number of contains relationships",
+	)
+}
+
+func TestUpdateKeepsUnchangedRelationships(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to write content")
+	markRelationships(d, standardContentPackage.UUID, asst)
+
+	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to rewrite content")
+
+	asst.Equal(
+		2,
+		countMarkedRelationships(d, standardContentPackage.UUID, asst),
+		"unchanged relationships should not have been recreated",
+	)
+	asst.Equal(
+		1,
+		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
+		"incorrect number of isCuratedFor relationships",
+	)
+	asst.Equal(
+		1,
+		checkContainsRelationship(d, contentPackageUUID, asst),
+		"incorrect number of contains relationships",
+	)
+}
+
+func TestUpdateReplacesOnlyChangedRelationships(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to write content")
+	markRelationships(d, standardContentPackage.UUID, asst)
+
+	updated := standardContentPackage
+	updated.StoryPackage = otherStoryPackageUUID
+	asst.NoError(s.Write(updated, "TEST_TRANS_ID"), "Failed to write updated content")
+
+	asst.Equal(
+		0,
+		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
+		"relationship to the previous story package should have been removed",
+	)
+	asst.Equal(
+		1,
+		checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst),
+		"relationship to the new story package should have been created",
+	)
+	asst.Equal(
+		1,
+		countMarkedRelationships(d, standardContentPackage.UUID, asst),
+		"the unchanged contains relationship should not have been recreated",
+	)
+}
+
+func TestWriteBatch(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	batch := []interface{}{
+		standardContentPackage,
+		videoContent,
+		graphicContent,
+		audioContent,
+		liveEventContent,
+		contentWithoutABody,
+	}
+	asst.NoError(s.WriteBatch(batch, "TEST_TRANS_ID"), "Failed to write content batch")
+
+	for _, c := range []content{standardContentPackage, videoContent, graphicContent, audioContent, liveEventContent} {
+		storedContent, found, err := s.Read(c.UUID, "TEST_TRANS_ID")
+		asst.NoError(err)
+		asst.True(found, "Content %s of the batch should have been written", c.UUID)
+		asst.Equal(c.Title, storedContent.(content).Title, "Failed to match Title")
+	}
+
+	_, found, err := s.Read(contentWithoutABody.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.False(found, "Content without a body should have been skipped")
+
+	asst.Equal(
+		1,
+		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
+		"incorrect number of isCuratedFor relationships",
+	)
+	asst.Equal(
+		1,
+		checkContainsRelationship(d, contentPackageUUID, asst),
+		"incorrect number of contains relationships",
+	)
+}
+
+func TestEditorialDeskIsPersisted(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	c := standardContent
+	c.EditorialDesk = " FT/ Standard Content/"
+	asst.NoError(s.Write(c, "TEST_TRANS_ID"), "Failed to write content")
+
+	storedContent, _, err := s.Read(c.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(standardEditorialDesk, storedContent.(content).EditorialDesk, "Editorial desk should be normalised")
+	asst.Equal(1, checkPublishedByDeskRelationship(d, standardEditorialDesk, asst))
+
+	c.EditorialDesk = otherEditorialDesk
+	asst.NoError(s.Write(c, "TEST_TRANS_ID"), "Failed to write updated content")
+
+	storedContent, _, err = s.Read(c.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(otherEditorialDesk, storedContent.(content).EditorialDesk)
+	asst.Equal(
+		0,
+		checkPublishedByDeskRelationship(d, standardEditorialDesk, asst),
+		"relationship to the previous desk should have been removed",
+	)
+	asst.Equal(1, checkPublishedByDeskRelationship(d, otherEditorialDesk, asst))
+
+	c.EditorialDesk = ""
+	asst.NoError(s.Write(c, "TEST_TRANS_ID"), "Failed to write content without desk")
+
+	storedContent, _, err = s.Read(c.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Empty(storedContent.(content).EditorialDesk)
+	asst.Equal(0, checkPublishedByDeskRelationship(d, otherEditorialDesk, asst))
+}
+
+func TestPublicationsAreStoredAsRelationships(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	c := standardContent
+	c.Publication = []string{publicationUUID, otherPublicationUUID}
+	asst.NoError(s.Write(c, "TEST_TRANS_ID"), "Failed to write content")
+
+	asst.Equal(1, checkPublishedInRelationship(d, publicationUUID, asst))
+	asst.Equal(1, checkPublishedInRelationship(d, otherPublicationUUID, asst))
+	asst.False(hasPublicationProperty(d, c.UUID, asst), "publication should not be stored as a property")
+
+	storedContent, _, err := s.Read(c.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]string{publicationUUID, otherPublicationUUID}, storedContent.(content).Publication)
+
+	c.Publication = []string{otherPublicationUUID, publicationUUID}
+	asst.NoError(s.Write(c, "TEST_TRANS_ID"), "Failed to write reordered content")
+
+	storedContent, _, err = s.Read(c.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(
+		[]string{otherPublicationUUID, publicationUUID},
+		storedContent.(content).Publication,
+		"publications should be read in the order they were last written",
+	)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write updated content")
+
+	asst.Equal(1, checkPublishedInRelationship(d, publicationUUID, asst))
+	asst.Equal(
+		0,
+		checkPublishedInRelationship(d, otherPublicationUUID, asst),
+		"relationship to the removed publication should have been deleted",
+	)
+}
+
+func TestMigratePublications(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	legacyContent := &cmneo4j.Query{
+		Cypher: `
+			UNWIND $uuids AS uuid
+			CREATE (n:Thing:Content {uuid: uuid, title: "Legacy", publication: [$publicationUUID]})`,
+		Params: map[string]interface{}{
+			"uuids":           []string{contentUUID, videoContentUUID, graphicUUID},
+			"publicationUUID": publicationUUID,
+		},
+	}
+	asst.NoError(d.Write(legacyContent))
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(
+		[]string{publicationUUID},
+		storedContent.(content).Publication,
+		"publication should be read from the property before the migration",
+	)
+
+	migrated, err := s.MigratePublications()
+	asst.NoError(err)
+	asst.Equal(3, migrated)
+
+	asst.Equal(3, checkPublishedInRelationship(d, publicationUUID, asst))
+	asst.False(hasPublicationProperty(d, contentUUID, asst), "publication property should have been removed")
+
+	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]string{publicationUUID}, storedContent.(content).Publication)
+
+	migrated, err = s.MigratePublications()
+	asst.NoError(err)
+	asst.Equal(0, migrated, "migration should be idempotent")
+}
+
+func TestPublishedDateIsStoredAsNativeDatetime(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	asst.True(
+		hasPublishedDateTime(d, contentUUID, standardContent.PublishedDate, asst),
+		"publishedDateTime should be the native datetime of publishedDate",
+	)
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(standardContent.PublishedDate, storedContent.(content).PublishedDate, "Read output should not change")
+
+	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write content without a published date")
+	asst.False(
+		hasPublishedDateTime(d, contentUUID, standardContent.PublishedDate, asst),
+		"publishedDateTime should be removed along with publishedDate",
+	)
+}
+
+func TestFirstPublishedDateKeepsTheEarliestDate(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	readFirstPublishedDate := func() string {
+		storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+		asst.NoError(err)
+		return storedContent.(content).FirstPublishedDate
+	}
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"))
+	asst.Equal(standardContent.PublishedDate, readFirstPublishedDate(), "first published date should default to the published date")
+
+	republished := standardContent
+	republished.PublishedDate = "1970-01-02T01:00:00.000Z"
+	asst.NoError(s.Write(republished, "TEST_TRANS_ID"))
+	asst.Equal(standardContent.PublishedDate, readFirstPublishedDate(), "earliest date should be kept on republish")
+
+	backdated := standardContent
+	backdated.PublishedDate = "1970-01-01T00:30:00.000Z"
+	asst.NoError(s.Write(backdated, "TEST_TRANS_ID"))
+	asst.Equal(backdated.PublishedDate, readFirstPublishedDate(), "earlier published date should become the first one")
+
+	explicit := republished
+	explicit.FirstPublishedDate = "1970-01-01T12:00:00+0100"
+	asst.NoError(s.Write(explicit, "TEST_TRANS_ID"))
+	asst.Equal("1970-01-01T11:00:00.000Z", readFirstPublishedDate(), "first published date of the payload should be stored")
+
+	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write content without dates")
+	asst.Equal("1970-01-01T11:00:00.000Z", readFirstPublishedDate(), "first published date should be kept without dates")
+}
+
+func TestFirstPublishedDateOfContentWrittenBeforeIt(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	tests := map[string]struct {
+		publishedDate              string
+		expectedFirstPublishedDate string
+	}{
+		"republished later": {
+			publishedDate:              "1970-01-02T01:00:00.000Z",
+			expectedFirstPublishedDate: standardContent.PublishedDate,
+		},
+		"backdated": {
+			publishedDate:              "1970-01-01T00:30:00.000Z",
+			expectedFirstPublishedDate: "1970-01-01T00:30:00.000Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer cleanDB(d, asst)
+
+			legacyContent := &cmneo4j.Query{
+				Cypher: `CREATE (n:Thing:Content:Article {uuid: $uuid, title: "Legacy", publishedDate: $publishedDate, publishedDateEpoch: $publishedDateEpoch})`,
+				Params: map[string]interface{}{
+					"uuid":               contentUUID,
+					"publishedDate":      standardContent.PublishedDate,
+					"publishedDateEpoch": 3600,
+				},
+			}
+			asst.NoError(d.Write(legacyContent))
+
+			republished := standardContent
+			republished.PublishedDate = test.publishedDate
+			asst.NoError(s.Write(republished, "TEST_TRANS_ID"))
+
+			storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+			asst.NoError(err)
+			asst.Equal(test.expectedFirstPublishedDate, storedContent.(content).FirstPublishedDate)
+		})
+	}
+}
+
+func TestMigratePublishedDateTime(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	legacyContent := &cmneo4j.Query{
+		Cypher: `
+			UNWIND $uuids AS uuid
+			CREATE (n:Thing:Content {uuid: uuid, title: "Legacy", publishedDate: $publishedDate})`,
+		Params: map[string]interface{}{
+			"uuids":         []string{contentUUID, videoContentUUID, graphicUUID},
+			"publishedDate": standardContent.PublishedDate,
+		},
+	}
+	asst.NoError(d.Write(legacyContent))
+	asst.NoError(s.Write(contentPlaceholder, "TEST_TRANS_ID"), "Failed to write content without a published date")
+
+	migrated, err := s.MigratePublishedDateTime()
+	asst.NoError(err)
+	asst.Equal(3, migrated)
+
+	for _, uuid := range []string{contentUUID, videoContentUUID, graphicUUID} {
+		asst.True(hasPublishedDateTime(d, uuid, standardContent.PublishedDate, asst), "content %s should have been migrated", uuid)
+	}
+
+	migrated, err = s.MigratePublishedDateTime()
+	asst.NoError(err)
+	asst.Equal(0, migrated, "migration should be idempotent")
+}
+
+func TestLiveBlogPostIsLinkedToItsPackage(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+
+	storedContent, _, err := s.Read(liveBlogPost.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(liveBlogUUID, storedContent.(content).LiveBlogPackage)
+	asst.Equal(1, checkIsPostOfRelationship(d, liveBlogUUID, asst))
+
+	movedPost := liveBlogPost
+	movedPost.LiveBlogPackage = otherLiveBlogUUID
+	asst.NoError(s.Write(movedPost, "TEST_TRANS_ID"), "Failed to write moved live blog post")
+
+	storedContent, _, err = s.Read(liveBlogPost.UUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(otherLiveBlogUUID, storedContent.(content).LiveBlogPackage)
+	asst.Equal(
+		0,
+		checkIsPostOfRelationship(d, liveBlogUUID, asst),
+		"relationship to the previous live blog should have been removed",
+	)
+	asst.Equal(1, checkIsPostOfRelationship(d, otherLiveBlogUUID, asst))
+}
+
+func TestLinksInBodyAreStoredAsRelationships(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	linkingContent := standardContent
+	linkingContent.Body = fmt.Sprintf(
+		`<body><p><a href="https://www.ft.com/content/%s">A link</a> and <ft-content type="http://www.ft.com/ontology/content/Article" url="http://api.ft.com/content/%s">another</ft-content></p></body>`,
+		thingUUID, conceptUUID,
+	)
+	asst.NoError(s.Write(linkingContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	asst.Equal(1, checkLinksToRelationship(d, thingUUID, asst))
+	asst.Equal(1, checkLinksToRelationship(d, conceptUUID, asst))
+
+	linkingContent.Body = fmt.Sprintf(`<body><p><a href="https://www.ft.com/content/%s">A link</a></p></body>`, thingUUID)
+	asst.NoError(s.Write(linkingContent, "TEST_TRANS_ID"), "Failed to republish content")
+
+	asst.Equal(1, checkLinksToRelationship(d, thingUUID, asst))
+	asst.Equal(0, checkLinksToRelationship(d, conceptUUID, asst), "link removed from the body should have been removed")
+
+	linkingContent.Body = `<body><p>Malformed</b></body>`
+	asst.NoError(s.Write(linkingContent, "TEST_TRANS_ID"), "Content with a malformed body should still be written")
+
+	asst.Equal(1, checkLinksToRelationship(d, thingUUID, asst), "links of the previous body should be kept")
+}
+
+func TestEmbedsInBodyAreStoredAsRelationships(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	embeddingContent := standardContent
+	embeddingContent.Body = fmt.Sprintf(
+		`<body><ft-content type="http://www.ft.com/ontology/content/ImageSet" url="http://api.ft.com/content/%s" data-embedded="true"></ft-content><p>Text</p><ft-content type="http://www.ft.com/ontology/content/Video" url="http://api.ft.com/content/%s" data-embedded="true"></ft-content></body>`,
+		thingUUID, videoContentUUID,
+	)
+	asst.NoError(s.Write(embeddingContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	asst.Equal([]int{0}, embedPositions(d, thingUUID, asst))
+	asst.Equal([]int{1}, embedPositions(d, videoContentUUID, asst))
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Nil(storedContent.(content).Embeds, "embeds should only be returned when requested")
+
+	storedContent, _, err = s.ReadWithOptions(contentUUID, "TEST_TRANS_ID", ReadOptions{IncludeEmbeds: true})
+	asst.NoError(err)
+	asst.Equal([]string{thingUUID, videoContentUUID}, storedContent.(content).Embeds)
+
+	embeddingContent.Body = fmt.Sprintf(
+		`<body><ft-content type="http://www.ft.com/ontology/content/Video" url="http://api.ft.com/content/%s" data-embedded="true"></ft-content></body>`,
+		videoContentUUID,
+	)
+	asst.NoError(s.Write(embeddingContent, "TEST_TRANS_ID"), "Failed to republish content")
+
+	asst.Empty(embedPositions(d, thingUUID, asst), "embed removed from the body should have been removed")
+	asst.Equal([]int{0}, embedPositions(d, videoContentUUID, asst), "position of the remaining embed should have been updated")
+
+	storedContent, _, err = s.ReadWithOptions(contentUUID, "TEST_TRANS_ID", ReadOptions{IncludeEmbeds: true})
+	asst.NoError(err)
+	asst.Equal([]string{videoContentUUID}, storedContent.(content).Embeds)
+
+	embeddingContent.Body = `<body><p>Malformed</b></body>`
+	asst.NoError(s.Write(embeddingContent, "TEST_TRANS_ID"), "Content with a malformed body should still be written")
+
+	asst.Equal([]int{0}, embedPositions(d, videoContentUUID, asst), "embeds of the previous body should be kept")
+}
+
+func TestBodyMetricsAreStoredAndRead(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	measuredContent := standardContent
+	measuredContent.Body = `<body><p>First paragraph of the article.</p><p></p><p>Second <em>and</em> last one.</p></body>`
+	asst.NoError(s.Write(measuredContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(bodyMetrics{WordCount: 9, ParagraphCount: 2, ReadingTimeMinutes: 1}, storedContent.(content).bodyMetrics)
+
+	measuredContent.Body = `<body><p>Malformed</b></body>`
+	asst.NoError(s.Write(measuredContent, "TEST_TRANS_ID"), "Content with a malformed body should still be written")
+
+	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(
+		bodyMetrics{WordCount: 9, ParagraphCount: 2, ReadingTimeMinutes: 1},
+		storedContent.(content).bodyMetrics,
+		"metrics of the previous body should be kept",
+	)
+}
+
+func TestReadBatchReturnsFoundAndMissingContent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+
+	batch, err := s.ReadBatch([]string{liveBlogPostUUID, thingUUID, contentUUID, liveBlogPostUUID}, "TEST_TRANS_ID", ReadOptions{})
+	asst.NoError(err)
+	asst.Equal([]string{thingUUID}, batch.Missing)
+
+	expectedPost, _, err := s.Read(liveBlogPostUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	expectedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]content{expectedPost.(content), expectedContent.(content)}, batch.Content, "content should be read as by Read, in the order of the UUIDs")
+
+	batch, err = s.ReadBatch([]string{thingUUID}, "TEST_TRANS_ID", ReadOptions{})
+	asst.NoError(err)
+	asst.Empty(batch.Content)
+	asst.Equal([]string{thingUUID}, batch.Missing)
+}
+
+func TestListFiltersAndPaginatesContent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	article := content{
+		UUID:          graphicUUID,
+		Title:         "Article",
+		PublishedDate: "1970-01-01T03:00:00.000Z",
+		Type:          "Article",
+		EditorialDesk: standardEditorialDesk,
+		Publication:   []string{otherPublicationUUID},
+	}
+	asst.NoError(s.Write(article, "TEST_TRANS_ID"), "Failed to write article")
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+
+	listedUUIDs := func(filter contentFilter) []string {
+		page, err := s.List(filter, "", 10, "TEST_TRANS_ID")
+		asst.NoError(err)
+		asst.Empty(page.NextCursor)
+		uuids := []string{}
+		for _, c := range page.Content {
+			uuids = append(uuids, c.UUID)
+		}
+		return uuids
+	}
+
+	from := int64(3600)
+	to := int64(7200)
+	asst.Equal([]string{graphicUUID}, listedUUIDs(contentFilter{Type: "Article"}))
+	asst.Equal([]string{contentUUID}, listedUUIDs(contentFilter{Publication: publicationUUID}))
+	asst.Equal([]string{graphicUUID, contentUUID}, listedUUIDs(contentFilter{EditorialDesk: standardEditorialDesk}))
+	asst.Equal([]string{liveBlogPostUUID, contentUUID}, listedUUIDs(contentFilter{PublishedFrom: &from, PublishedTo: &to}))
+	asst.Empty(listedUUIDs(contentFilter{Type: "Article", Publication: publicationUUID}))
+
+	page, err := s.List(contentFilter{}, "", 2, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Len(page.Content, 2)
+	asst.Equal(graphicUUID, page.Content[0].UUID)
+	asst.Equal(liveBlogPostUUID, page.Content[1].UUID)
+	asst.NotEmpty(page.NextCursor)
+
+	page, err = s.List(contentFilter{}, page.NextCursor, 2, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Empty(page.NextCursor, "the last page should not have a next cursor")
+	expected, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]content{expected.(content)}, page.Content, "content should be listed as read by Read")
+}
+
+func TestCountByBreaksDownContent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	article := content{
+		UUID:          graphicUUID,
+		Title:         "Article",
+		PublishedDate: "1970-02-01T03:00:00.000Z",
+		Type:          "Article",
+		Publication:   []string{publicationUUID, otherPublicationUUID},
+	}
+	asst.NoError(s.Write(article, "TEST_TRANS_ID"), "Failed to write article")
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+	asst.NoError(s.Write(liveEventContent, "TEST_TRANS_ID"), "Failed to write live event")
+
+	key := func(k string) *string { return &k }
+
+	breakdown, err := s.CountBy("type", contentFilter{})
+	asst.NoError(err)
+	asst.Equal(countBreakdown{GroupBy: "type", Total: 4, Groups: []countGroup{
+		{Key: key("Article"), Count: 1},
+		{Key: key("Content"), Count: 1},
+		{Key: key("LiveBlogPost"), Count: 1},
+		{Key: key("LiveEvent"), Count: 1},
+	}}, breakdown)
+
+	breakdown, err = s.CountBy("publication", contentFilter{})
+	asst.NoError(err)
+	asst.Equal(countBreakdown{GroupBy: "publication", Total: 4, Groups: []countGroup{
+		{Key: key(otherPublicationUUID), Count: 1},
+		{Key: key(publicationUUID), Count: 2},
+		{Key: nil, Count: 2},
+	}}, breakdown)
+
+	from := int64(0)
+	breakdown, err = s.CountBy("month", contentFilter{PublishedFrom: &from})
+	asst.NoError(err)
+	asst.Equal(countBreakdown{GroupBy: "month", Total: 3, Groups: []countGroup{
+		{Key: key("1970-01"), Count: 2},
+		{Key: key("1970-02"), Count: 1},
+	}}, breakdown)
+
+	count, err := s.CountFiltered(contentFilter{Type: "Article"})
+	asst.NoError(err)
+	asst.Equal(1, count)
+
+	count, err = s.Count()
+	asst.NoError(err)
+	asst.Equal(4, count)
+}
+
+func TestSearchRanksTitleMatches(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	article := content{
+		UUID:          graphicUUID,
+		Title:         "Profits plunge at Vatican bank",
+		PublishedDate: "2014-07-08T13:52:52.000Z",
+		Type:          "Article",
+	}
+	post := liveBlogPost
+	post.Title = "Vatican: live updates"
+	asst.NoError(s.Write(article, "TEST_TRANS_ID"), "Failed to write article")
+	asst.NoError(s.Write(post, "TEST_TRANS_ID"), "Failed to write live blog post")
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	page, err := s.Search("vatican bank profits", "", 0, 10)
+	asst.NoError(err)
+	asst.Equal(2, page.Total)
+	if asst.Len(page.Results, 2) {
+		asst.Equal(graphicUUID, page.Results[0].UUID, "the best match should come first")
+		asst.Equal(article.Title, page.Results[0].Title)
+		asst.Equal("Article", page.Results[0].Type)
+		asst.Equal(article.PublishedDate, page.Results[0].PublishedDate)
+		asst.Equal(liveBlogPostUUID, page.Results[1].UUID)
+		asst.Greater(page.Results[0].Score, page.Results[1].Score)
+	}
+
+	page, err = s.Search("vatican bank profits", "", 1, 1)
+	asst.NoError(err)
+	asst.Equal(2, page.Total)
+	if asst.Len(page.Results, 1) {
+		asst.Equal(liveBlogPostUUID, page.Results[0].UUID)
+	}
+
+	page, err = s.Search("Vatican:", "LiveBlogPost", 0, 10)
+	asst.NoError(err)
+	asst.Equal(1, page.Total)
+	if asst.Len(page.Results, 1) {
+		asst.Equal(liveBlogPostUUID, page.Results[0].UUID)
+	}
+
+	page, err = s.Search("nothing matches", "", 0, 10)
+	asst.NoError(err)
+	asst.Equal(0, page.Total)
+	asst.Empty(page.Results)
+}
+
+func TestMigrateSchemaAppliesPendingMigrationsOnce(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+
+	latest := schemaMigrations[len(schemaMigrations)-1]
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MATCH (m:SchemaMigration {version: $version}) DELETE m`,
+		Params: map[string]interface{}{"version": latest.Version},
+	}))
+
+	status, err := s.SchemaStatus()
+	asst.NoError(err)
+	asst.Equal(latest.Version-1, status.CurrentVersion)
+	asst.Equal(latest.Version, status.LatestVersion)
+	asst.False(status.Migrations[len(status.Migrations)-1].Applied)
+
+	applied, err := s.MigrateSchema(0)
+	asst.NoError(err)
+	asst.Equal(1, applied, "only the pending migration should be applied")
+
+	applied, err = s.MigrateSchema(0)
+	asst.NoError(err)
+	asst.Equal(0, applied, "applied migrations should not be applied again")
+
+	status, err = s.SchemaStatus()
+	asst.NoError(err)
+	asst.Equal(latest.Version, status.CurrentVersion)
+	asst.Empty(status.LockedBy, "the lock should be released")
+	for _, m := range status.Migrations {
+		asst.True(m.Applied, "migration %d should be applied", m.Version)
+		asst.NotEmpty(m.AppliedAt)
+	}
+}
+
+func TestMigrateSchemaWaitsForTheLock(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MERGE (l:SchemaMigrationLock {name: $name})
+			SET l.owner = 'other-instance', l.lockedUntil = timestamp() + 60000`,
+		Params: map[string]interface{}{"name": schemaLockName},
+	}))
+	defer func() {
+		asst.NoError(d.Write(&cmneo4j.Query{
+			Cypher: `MATCH (l:SchemaMigrationLock {name: $name}) REMOVE l.owner, l.lockedUntil`,
+			Params: map[string]interface{}{"name": schemaLockName},
+		}))
+	}()
+
+	_, err := s.MigrateSchema(0)
+	asst.ErrorIs(err, ErrSchemaMigrationLocked)
+
+	status, err := s.SchemaStatus()
+	asst.NoError(err)
+	asst.Equal("other-instance", status.LockedBy)
+
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MATCH (l:SchemaMigrationLock {name: $name}) SET l.lockedUntil = timestamp() - 1`,
+		Params: map[string]interface{}{"name": schemaLockName},
+	}))
+
+	_, err = s.MigrateSchema(0)
+	asst.NoError(err, "an expired lock should be taken over")
+}
+
+func TestCollectPlaceholdersDeletesOnlyOrphanedPlaceholders(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	// the story package placeholder is orphaned once the content is republished without it,
+	// while the publication keeps its label and the live blog placeholder its relationship.
+	// Bare things merged by other writers are not tagged, and tagged ones they enriched hold more properties.
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	republished := standardContent
+	republished.StoryPackage = ""
+	asst.NoError(s.Write(republished, "TEST_TRANS_ID"), "Failed to republish content")
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+	writeNodeWithLabels(d, thingUUID, "Thing", asst)
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MERGE (t:Thing {uuid: $uuid}) SET t.placeholderOf = $placeholderOf, t.prefLabel = 'Not a placeholder'`,
+		Params: map[string]interface{}{"uuid": conceptUUID, "placeholderOf": placeholderTag},
+	}))
+
+	var tags []struct {
+		UUID string `json:"uuid"`
+		Tag  string `json:"tag"`
+	}
+	asst.NoError(d.Read(&cmneo4j.Query{
+		Cypher: `MATCH (t:Thing) WHERE t.uuid IN $uuids
+			RETURN t.uuid AS uuid, coalesce(t.placeholderOf, '') AS tag`,
+		Params: map[string]interface{}{"uuids": []string{storyPackageUUID, publicationUUID}},
+		Result: &tags,
+	}))
+	asst.ElementsMatch([]struct {
+		UUID string `json:"uuid"`
+		Tag  string `json:"tag"`
+	}{
+		{UUID: storyPackageUUID, Tag: placeholderTag},
+		{UUID: publicationUUID, Tag: ""},
+	}, tags, "only plain things should be tagged as placeholders")
+
+	report, err := s.CollectPlaceholders(true)
+	asst.NoError(err)
+	asst.True(report.DryRun)
+	asst.GreaterOrEqual(report.Count, 1)
+	asst.Contains(report.UUIDs, storyPackageUUID)
+	for _, uuid := range []string{publicationUUID, liveBlogUUID, conceptUUID, thingUUID} {
+		asst.NotContains(report.UUIDs, uuid)
+	}
+
+	exists, err := doesThingExist(storyPackageUUID, d)
+	asst.NoError(err)
+	asst.True(exists, "a dry run should not delete anything")
+
+	report, err = s.CollectPlaceholders(false)
+	asst.NoError(err)
+	asst.False(report.DryRun)
+	asst.GreaterOrEqual(report.Count, 1)
+
+	for uuid, expected := range map[string]bool{
+		storyPackageUUID: false,
+		thingUUID:        true,
+		publicationUUID:  true,
+		liveBlogUUID:     true,
+		conceptUUID:      true,
+		contentUUID:      true,
+	} {
+		exists, err := doesThingExist(uuid, d)
+		asst.NoError(err)
+		asst.Equal(expected, exists, "unexpected existence of %s", uuid)
+	}
+}
+
+func TestCollectPlaceholdersFailsWhileAnotherInstanceCollectsThem(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+	republished := standardContent
+	republished.StoryPackage = ""
+	asst.NoError(s.Write(republished, "TEST_TRANS_ID"), "Failed to republish content")
+
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MERGE (l:PlaceholderCollectionLock {name: $name})
+			SET l.owner = 'other-instance', l.lockedUntil = timestamp() + 60000`,
+		Params: map[string]interface{}{"name": placeholderCollectionLock.name},
+	}))
+	defer func() {
+		asst.NoError(d.Write(&cmneo4j.Query{
+			Cypher: `MATCH (l:PlaceholderCollectionLock {name: $name}) DELETE l`,
+			Params: map[string]interface{}{"name": placeholderCollectionLock.name},
+		}))
+	}()
+
+	_, err := s.CollectPlaceholders(false)
+	asst.ErrorIs(err, ErrPlaceholderCollectionLocked)
+	exists, err := doesThingExist(storyPackageUUID, d)
+	asst.NoError(err)
+	asst.True(exists, "nothing should be deleted while another instance collects the placeholders")
+
+	report, err := s.CollectPlaceholders(true)
+	asst.NoError(err, "a dry run should not need the lock")
+	asst.Contains(report.UUIDs, storyPackageUUID)
+
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MATCH (l:PlaceholderCollectionLock {name: $name}) SET l.lockedUntil = timestamp() - 1`,
+		Params: map[string]interface{}{"name": placeholderCollectionLock.name},
+	}))
+
+	_, err = s.CollectPlaceholders(false)
+	asst.NoError(err, "an expired lock should be taken over")
+	exists, err = doesThingExist(storyPackageUUID, d)
+	asst.NoError(err)
+	asst.False(exists)
+}
+
+func TestMembersOfLiveBlogArePaginatedFromTheLatest(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	laterPost := liveBlogPost
+	laterPost.UUID = thingUUID
+	laterPost.Title = "Later live blog post"
+	laterPost.PublishedDate = "1970-01-01T03:00:00.000Z"
+
+	asst.NoError(s.Write(liveBlogPost, "TEST_TRANS_ID"), "Failed to write live blog post")
+	asst.NoError(s.Write(laterPost, "TEST_TRANS_ID"), "Failed to write later live blog post")
+
+	page, found, err := s.Members(liveBlogUUID, 0, 10)
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(2, page.Total)
+	asst.Equal([]member{
+		{UUID: thingUUID, Type: "LiveBlogPost", Title: laterPost.Title, PublishedDate: laterPost.PublishedDate},
+		{UUID: liveBlogPostUUID, Type: "LiveBlogPost", Title: liveBlogPost.Title, PublishedDate: liveBlogPost.PublishedDate},
+	}, page.Members)
+
+	page, found, err = s.Members(liveBlogUUID, 1, 1)
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(2, page.Total)
+	asst.Equal(1, page.Offset)
+	asst.Equal(1, page.Limit)
+	asst.Len(page.Members, 1)
+	asst.Equal(liveBlogPostUUID, page.Members[0].UUID)
+
+	page, found, err = s.Members(liveBlogUUID, 2, 1)
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(2, page.Total)
+	asst.Empty(page.Members)
+}
+
+func TestMembersOfStoryPackage(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	page, found, err := s.Members(storyPackageUUID, 0, 10)
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(1, page.Total)
+	asst.Equal([]member{
+		{UUID: contentUUID, Title: standardContent.Title, PublishedDate: standardContent.PublishedDate},
+	}, page.Members)
+
+	page, found, err = s.Members(contentUUID, 0, 10)
+	asst.NoError(err)
+	asst.True(found, "content without members should be found")
+	asst.Equal(0, page.Total)
+	asst.Empty(page.Members)
+}
+
+func TestMembersOfContentPackageAreTheContentOfItsCollection(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	packaged := content{
+		UUID:          thingUUID,
+		Title:         "Packaged content",
+		PublishedDate: "1970-01-01T02:00:00.000Z",
+		Body:          "Some body",
+	}
+	asst.NoError(s.Write(genericContentPackage, "TEST_TRANS_ID"), "Failed to write content package")
+	asst.NoError(s.Write(packaged, "TEST_TRANS_ID"), "Failed to write packaged content")
+	asst.NoError(d.Write(&cmneo4j.Query{
+		Cypher: `MATCH (cc:Thing {uuid: $collectionUUID}), (c:Content {uuid: $uuid})
+			MERGE (cc)-[:CONTAINS]->(c)`,
+		Params: map[string]interface{}{
+			"collectionUUID": genericContentPackageUUID,
+			"uuid":           thingUUID,
+		},
+	}))
+
+	page, found, err := s.Members(genericContentPackage.UUID, 0, 10)
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(1, page.Total, "the collection itself should not be a member")
+	asst.Equal([]member{
+		{UUID: thingUUID, Title: packaged.Title, PublishedDate: packaged.PublishedDate},
+	}, page.Members)
+}
+
+func TestMembersOfMissingPackage(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	_, found, err := s.Members(conceptUUID, 0, 10)
+	asst.NoError(err)
+	asst.False(found)
+}
+
+func TestWriteCalculateEpocCorrectly(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	uuid := standardContent.UUID
+	contentReceived := content{
+		UUID:          uuid,
+		Title:         "TestContent",
+		PublishedDate: "1970-01-01T01:00:00.000Z",
+		Body:          "Some Test text",
+	}
+	err := s.Write(contentReceived, "TEST_TRANS_ID")
+	asst.NoError(err)
+
+	var result []struct {
+		PublishedDateEpoc int `json:"t.publishedDateEpoch"`
+	}
+
+	getEpochQuery := &cmneo4j.Query{
+		Cypher: `
+			MATCH (t:Content {uuid: $uuid}) RETURN t.publishedDateEpoch
+			`,
+		Params: map[string]interface{}{
+			"uuid": standardContent.UUID,
+		},
+		Result: &result,
+	}
+
+	err = d.Write(getEpochQuery)
+	asst.NoError(err)
+	asst.Equal(3600, result[0].PublishedDateEpoc, "Epoc of 1970-01-01T01:00:00.000Z should be 3600")
+}
+
+func TestWriteNormalisesPublishedDate(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	offsetContent := standardContent
+	offsetContent.PublishedDate = "1970-01-01T02:00:00+0100"
+	asst.NoError(s.Write(offsetContent, "TEST_TRANS_ID"))
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal("1970-01-01T01:00:00.000Z", storedContent.(content).PublishedDate)
+}
+
+func TestWriteRejectsInvalidPublishedDate(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	invalidContent := standardContent
+	invalidContent.PublishedDate = "01/01/1970"
+	err := s.Write(invalidContent, "TEST_TRANS_ID")
+
+	var validationErr *ValidationError
+	asst.True(errors.As(err, &validationErr), "expected a validation error but got %v", err)
+
+	exists, err := doesThingExist(contentUUID, d)
+	asst.NoError(err)
+	asst.False(exists, "invalid content should not have been written")
+}
+
+func TestPatchKeepsTheFieldsAndRelationshipsItDoesNotChange(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	linkingContent := standardContent
+	linkingContent.Body = `<body><p>See <a href="https://www.ft.com/content/` + thingUUID + `">this</a>.</p></body>`
+	asst.NoError(s.Write(linkingContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	found, err := s.Patch(contentUUID, map[string]interface{}{"title": "Patched title"}, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.True(found)
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	patched := storedContent.(content)
+	asst.Equal("Patched title", patched.Title)
+	asst.Equal(standardContent.PublishedDate, patched.PublishedDate)
+	asst.Equal([]string{storyPackageUUID}, patched.StoryPackages)
+	asst.Equal(bodyMetrics{WordCount: 2, ParagraphCount: 1, ReadingTimeMinutes: 1}, patched.bodyMetrics)
+
+	asst.Equal(1, checkIsCuratedForRelationship(d, storyPackageUUID, asst), "incorrect number of isCuratedFor relationships")
+	asst.Equal(1, checkPublishedByDeskRelationship(d, standardEditorialDesk, asst), "incorrect number of publishedByDesk relationships")
+	asst.Equal(1, checkPublishedInRelationship(d, publicationUUID, asst), "incorrect number of publishedIn relationships")
+	asst.Equal(1, checkLinksToRelationship(d, thingUUID, asst), "links of the stored body should be kept")
+}
+
+func TestPatchReplacesAndRemovesFields(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	found, err := s.Patch(contentUUID, map[string]interface{}{
+		"storyPackages": []interface{}{otherStoryPackageUUID},
+		"editorialDesk": nil,
+		"body":          `<body><p>A new body</p></body>`,
+	}, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.True(found)
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	patched := storedContent.(content)
+	asst.Equal([]string{otherStoryPackageUUID}, patched.StoryPackages)
+	asst.Empty(patched.EditorialDesk)
+	asst.Equal(bodyMetrics{WordCount: 3, ParagraphCount: 1, ReadingTimeMinutes: 1}, patched.bodyMetrics)
+
+	asst.Equal(0, checkIsCuratedForRelationship(d, storyPackageUUID, asst), "the replaced story package should be removed")
+	asst.Equal(1, checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst), "incorrect number of isCuratedFor relationships")
+	asst.Equal(0, checkPublishedByDeskRelationship(d, standardEditorialDesk, asst), "the removed editorial desk should be removed")
+}
+
+func TestPatchOfMissingContent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	found, err := s.Patch(contentUUID, map[string]interface{}{"title": "Patched title"}, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.False(found)
+
+	exists, err := doesThingExist(contentUUID, d)
+	asst.NoError(err)
+	asst.False(exists, "missing content should not be created by a patch")
+}
+
+func TestPatchRejectsInvalidContent(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	for _, patch := range []map[string]interface{}{
+		{"uuid": thingUUID},
+		{"publishedDate": "01/01/1970"},
+		{"storyPackages": "not a list"},
+	} {
+		_, err := s.Patch(contentUUID, patch, "TEST_TRANS_ID")
+		var validationErr *ValidationError
+		asst.True(errors.As(err, &validationErr), "expected a validation error for %v but got %v", patch, err)
+	}
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(standardContent.PublishedDate, storedContent.(content).PublishedDate)
+}
+
+func TestPatchIsNotAppliedOverAConcurrentWrite(t *testing.T) {
+	asst := assert.New(t)
+	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
+	a := getAgent(defaultPolicy, l, t)
+	d := getDriverAndCheckClean(t, asst, l)
+	s := getContentService(d, a, l)
+	defer cleanDB(d, asst)
+
+	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
+
+	read, found, err := s.readContent(contentUUID, ReadOptions{})
+	asst.NoError(err)
+	asst.True(found)
+	asst.Equal(int64(1), read.Version)
+
+	// the content is written between the read and the write of the patch
+	concurrent := standardContent
+	concurrent.Title = "Concurrent title"
+	asst.NoError(s.Write(concurrent, "TEST_TRANS_ID"), "Failed to write concurrent content")
+
+	stored := read.toContent()
+	patched := stored
+	patched.Title = "Patched title"
+	item, ok, err := s.writeItem(patched, &stored)
+	asst.NoError(err)
+	asst.True(ok)
+	asst.ErrorIs(s.writeVersion(getContentLabels(patched), item, read.Version), ErrPatchConflict)
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(concurrent.Title, storedContent.(content).Title, "the concurrent write should not be overwritten")
+
+	_, err = s.AddStoryPackage(contentUUID, otherStoryPackageUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+
+	read, _, err = s.readContent(contentUUID, ReadOptions{})
+	asst.NoError(err)
+	asst.Equal(int64(3), read.Version, "adding a package should increment the version")
+
+	found, err = s.Patch(contentUUID, map[string]interface{}{"title": "Patched title"}, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.True(found)
+
+	read, _, err = s.readContent(contentUUID, ReadOptions{})
+	asst.NoError(err)
+	asst.Equal(int64(4), read.Version)
+	asst.Equal("Patched title", read.Title)
+	asst.ElementsMatch([]string{storyPackageUUID, otherStoryPackageUUID}, read.StoryPackages, "the added package should be kept")
 }
 
-func TestCreateAllValuesPresent(t *testing.T) {
+func TestPatchOfSpecialContentIsNotWritten(t *testing.T) {
 	asst := assert.New(t)
 	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
-	a := getAgent(defaultPolicy, l, t)
+	a := getAgent(specialContentPolicy, l, t)
 	d := getDriverAndCheckClean(t, asst, l)
 	s := getContentService(d, a, l)
 	defer cleanDB(d, asst)
 
 	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
 
-	storedContent, _, err := s.Read(standardContent.UUID, "TEST_TRANS_ID")
-	asst.NoError(err)
-	asst.NotEmpty(storedContent, "Failed to retrieve stored content")
-	actualContent := storedContent.(content)
+	found, err := s.Patch(contentUUID, map[string]interface{}{
+		"title":         "Patched title",
+		"editorialDesk": "/FT/Professional/Central Banking",
+	}, "TEST_TRANS_ID")
+	asst.ErrorIs(err, ErrPatchNotWritten)
+	asst.True(found)
 
-	asst.Equal(standardContent.UUID, actualContent.UUID, "Failed to match UUID")
-	asst.Equal(standardContent.Title, actualContent.Title, "Failed to match Title")
-	asst.Equal(
-		standardContent.PublishedDate,
-		actualContent.PublishedDate,
-		"Failed to match PublishedDate",
-	)
-	asst.Empty(actualContent.Body, "Body should not have been stored")
-	asst.Equal(standardContent.StoryPackage, actualContent.StoryPackage)
-	asst.Equal(standardContent.ContentPackage, actualContent.ContentPackage)
-	asst.Equal(standardContent.Publication, actualContent.Publication)
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal(standardContent.Title, storedContent.(content).Title, "special content should not be written")
 }
 
-func TestCreateNotAllValuesPresent(t *testing.T) {
+func TestPatchToATypeNeedingABodyIsNotWritten(t *testing.T) {
 	asst := assert.New(t)
 	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
 	a := getAgent(defaultPolicy, l, t)
@@ -400,16 +1939,22 @@ func TestCreateNotAllValuesPresent(t *testing.T) {
 	s := getContentService(d, a, l)
 	defer cleanDB(d, asst)
 
-	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write content")
+	asst.NoError(s.Write(videoContent, "TEST_TRANS_ID"), "Failed to write video")
 
-	storedContent, _, err := s.Read(shorterContent.UUID, "TEST_TRANS_ID")
+	found, err := s.Patch(videoContent.UUID, map[string]interface{}{"type": "Image"}, "TEST_TRANS_ID")
+	asst.ErrorIs(err, ErrPatchNotWritten, "content without a body should not be patched to a type needing one")
+	asst.True(found)
 
+	storedContent, _, err := s.Read(videoContent.UUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.Empty(storedContent.(content).PublishedDate)
-	asst.Empty(storedContent.(content).Title)
+	asst.Equal(videoContent.Type, storedContent.(content).Type)
+
+	found, err = s.Patch(videoContent.UUID, map[string]interface{}{"title": "Patched title"}, "TEST_TRANS_ID")
+	asst.NoError(err, "content keeping its type should stay eligible without a body")
+	asst.True(found)
 }
 
-func TestWillUpdateProperties(t *testing.T) {
+func TestStoryPackagesCanBeAddedAndRemoved(t *testing.T) {
 	asst := assert.New(t)
 	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
 	a := getAgent(defaultPolicy, l, t)
@@ -418,29 +1963,30 @@ func TestWillUpdateProperties(t *testing.T) {
 	defer cleanDB(d, asst)
 
 	asst.NoError(s.Write(standardContent, "TEST_TRANS_ID"), "Failed to write content")
-	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
 
+	for i := 0; i < 2; i++ {
+		found, err := s.AddStoryPackage(contentUUID, otherStoryPackageUUID, "TEST_TRANS_ID")
+		asst.NoError(err)
+		asst.True(found)
+	}
+	asst.Equal(1, checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst), "incorrect number of isCuratedFor relationships")
+
+	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.Equal(storedContent.(content).Title, standardContent.Title)
-	asst.Equal(storedContent.(content).PublishedDate, standardContent.PublishedDate)
+	asst.ElementsMatch([]string{storyPackageUUID, otherStoryPackageUUID}, storedContent.(content).StoryPackages)
 
-	asst.NoError(s.Write(updatedContent, "TEST_TRANS_ID"), "Failed to write updated content")
-	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+	removed, err := s.RemoveStoryPackage(contentUUID, storyPackageUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.True(removed)
+	asst.Equal(0, checkIsCuratedForRelationship(d, storyPackageUUID, asst), "the story package should be removed")
+	asst.Equal(1, checkIsCuratedForRelationship(d, otherStoryPackageUUID, asst), "the other story package should be kept")
 
+	removed, err = s.RemoveStoryPackage(contentUUID, storyPackageUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.Equal(
-		storedContent.(content).Title,
-		updatedContent.Title,
-		"Should have updated Title but it is still present",
-	)
-	asst.Equal(
-		storedContent.(content).PublishedDate,
-		updatedContent.PublishedDate,
-		"Should have updated PublishedDate but it is still present",
-	)
+	asst.False(removed, "a missing relationship should not be reported as removed")
 }
 
-func TestUpdateWillRemovePropertiesNoLongerPresent(t *testing.T) {
+func TestContentPackagesCanBeAddedAndRemovedWithTheirLabels(t *testing.T) {
 	asst := assert.New(t)
 	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
 	a := getAgent(defaultPolicy, l, t)
@@ -448,49 +1994,30 @@ func TestUpdateWillRemovePropertiesNoLongerPresent(t *testing.T) {
 	s := getContentService(d, a, l)
 	defer cleanDB(d, asst)
 
-	asst.NoError(s.Write(standardContentPackage, "TEST_TRANS_ID"), "Failed to write content")
-	storedContent, _, err := s.Read(contentUUID, "TEST_TRANS_ID")
+	liveBlog := content{UUID: liveBlogUUID, Title: "Live blog", Type: LiveBlogPackage}
+	asst.NoError(s.Write(liveBlog, "TEST_TRANS_ID"), "Failed to write live blog")
 
+	found, err := s.AddContentPackage(liveBlogUUID, contentPackageUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.NotEmpty(storedContent.(content).Title)
-	asst.NotEmpty(storedContent.(content).PublishedDate)
-	asst.Equal(
-		1,
-		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
-		"incorrect number of isCuratedFor relationships",
-	)
-	asst.Equal(
-		1,
-		checkContainsRelationship(d, contentPackageUUID, asst),
-		"incorrect number of contains relationships",
-	)
+	asst.True(found)
+	asst.Equal(1, checkContainsRelationship(d, contentPackageUUID, asst), "incorrect number of contains relationships")
 
-	asst.NoError(s.Write(shorterContent, "TEST_TRANS_ID"), "Failed to write updated content")
-	storedContent, _, err = s.Read(contentUUID, "TEST_TRANS_ID")
+	storedContent, _, err := s.Read(liveBlogUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]string{contentPackageUUID}, storedContent.(content).ContentPackages)
+	asst.Equal([]string{"Content", "ContentPackage", LiveBlogPackage, "Thing"}, storedContent.(content).Labels)
 
+	removed, err := s.RemoveContentPackage(liveBlogUUID, contentPackageUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.NotEmpty(storedContent, "Failed to rеtriеve updated content")
-	asst.Empty(
-		storedContent.(content).Title,
-		"Update should have removed Title but it is still present",
-	)
-	asst.Empty(
-		storedContent.(content).PublishedDate,
-		"Update should have removed PublishedDate but it is still present",
-	)
-	asst.Equal(
-		0,
-		checkIsCuratedForRelationship(d, storyPackageUUID, asst),
-		"incorrect number of isCuratedFor relationships",
-	)
-	asst.Equal(
-		0,
-		checkContainsRelationship(d, contentPackageUUID, asst),
-		"incorrect number of contains relationships",
-	)
+	asst.True(removed)
+	asst.Equal(0, checkContainsRelationship(d, contentPackageUUID, asst), "the content package should be removed")
+
+	storedContent, _, err = s.Read(liveBlogUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.Equal([]string{"Content", "Thing"}, storedContent.(content).Labels, "labels derived from content packages should be removed")
 }
 
-func TestWriteCalculateEpocCorrectly(t *testing.T) {
+func TestPackagesOfMissingContent(t *testing.T) {
 	asst := assert.New(t)
 	l := logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")
 	a := getAgent(defaultPolicy, l, t)
@@ -498,33 +2025,23 @@ func TestWriteCalculateEpocCorrectly(t *testing.T) {
 	s := getContentService(d, a, l)
 	defer cleanDB(d, asst)
 
-	uuid := standardContent.UUID
-	contentReceived := content{
-		UUID:          uuid,
-		Title:         "TestContent",
-		PublishedDate: "1970-01-01T01:00:00.000Z",
-		Body:          "Some Test text",
-	}
-	err := s.Write(contentReceived, "TEST_TRANS_ID")
+	found, err := s.AddStoryPackage(contentUUID, storyPackageUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
+	asst.False(found)
 
-	var result []struct {
-		PublishedDateEpoc int `json:"t.publishedDateEpoch"`
-	}
-
-	getEpochQuery := &cmneo4j.Query{
-		Cypher: `
-			MATCH (t:Content {uuid: $uuid}) RETURN t.publishedDateEpoch
-			`,
-		Params: map[string]interface{}{
-			"uuid": standardContent.UUID,
-		},
-		Result: &result,
-	}
+	found, err = s.AddContentPackage(contentUUID, contentPackageUUID, "TEST_TRANS_ID")
+	asst.NoError(err)
+	asst.False(found)
 
-	err = d.Write(getEpochQuery)
+	removed, err := s.RemoveContentPackage(contentUUID, contentPackageUUID, "TEST_TRANS_ID")
 	asst.NoError(err)
-	asst.Equal(3600, result[0].PublishedDateEpoc, "Epoc of 1970-01-01T01:00:00.000Z should be 3600")
+	asst.False(removed)
+
+	for _, uuid := range []string{contentUUID, storyPackageUUID, contentPackageUUID} {
+		exists, err := doesThingExist(uuid, d)
+		asst.NoError(err)
+		asst.False(exists, "no node should be created for missing content")
+	}
 }
 
 func TestWritePrefLabelIsAlsoWrittenAndIsEqualToTitle(t *testing.T) {
@@ -797,7 +2314,7 @@ func testContentWillBeWritten(t *testing.T, c content) {
 }
 
 func getDriverAndCheckClean(
-	t *testing.T,
+	t testing.TB,
 	a *assert.Assertions,
 	l *logger.UPPLogger,
 ) *cmneo4j.Driver {
@@ -827,6 +2344,7 @@ func cleanDB(d *cmneo4j.Driver, a *assert.Assertions) {
 		contentPlaceholderUUID,
 		videoContentUUID,
 		storyPackageUUID,
+		otherStoryPackageUUID,
 		contentPackageUUID,
 		contentCollectionUUID,
 		thingUUID,
@@ -834,6 +2352,10 @@ func cleanDB(d *cmneo4j.Driver, a *assert.Assertions) {
 		graphicUUID,
 		audioContentUUID,
 		liveEventUUID,
+		publicationUUID,
+		otherPublicationUUID,
+		liveBlogPostUUID,
+		otherLiveBlogUUID,
 	}
 
 	var qs []*cmneo4j.Query
@@ -846,6 +2368,13 @@ func cleanDB(d *cmneo4j.Driver, a *assert.Assertions) {
 		})
 	}
 
+	qs = append(qs, &cmneo4j.Query{
+		Cypher: `MATCH (d:EditorialDesk) WHERE d.path IN $paths DETACH DELETE d`,
+		Params: map[string]interface{}{
+			"paths": []string{standardEditorialDesk, otherEditorialDesk},
+		},
+	})
+
 	err := d.Write(qs...)
 	a.NoError(err)
 }
@@ -981,7 +2510,191 @@ func checkContainsRelationship(d *cmneo4j.Driver, cpID string, a *assert.Asserti
 	return results[0].Count
 }
 
-func checkDBClean(d *cmneo4j.Driver, t *testing.T) {
+func checkPublishedByDeskRelationship(d *cmneo4j.Driver, desk string, a *assert.Assertions) int {
+	countQuery := `
+		MATCH (x)-[r:PUBLISHED_BY_DESK]->(d:EditorialDesk{path:$desk})
+		RETURN count(r) as c`
+
+	var results []struct {
+		Count int `json:"c"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: countQuery,
+		Params: map[string]interface{}{"desk": desk},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].Count
+}
+
+func checkPublishedInRelationship(d *cmneo4j.Driver, publicationID string, a *assert.Assertions) int {
+	countQuery := `
+		MATCH (x)-[r:PUBLISHED_IN]->(p:Thing:Publication{uuid:$publicationId})
+		RETURN count(r) as c`
+
+	var results []struct {
+		Count int `json:"c"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: countQuery,
+		Params: map[string]interface{}{"publicationId": publicationID},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].Count
+}
+
+func hasPublicationProperty(d *cmneo4j.Driver, contentID string, a *assert.Assertions) bool {
+	var results []struct {
+		HasProperty bool `json:"hasProperty"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: `
+			MATCH (t:Thing{uuid:$contentId})
+			RETURN t.publication IS NOT NULL as hasProperty`,
+		Params: map[string]interface{}{"contentId": contentID},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].HasProperty
+}
+
+func hasPublishedDateTime(d *cmneo4j.Driver, contentID string, publishedDate string, a *assert.Assertions) bool {
+	var results []struct {
+		Matches bool `json:"matches"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: `
+			MATCH (t:Thing{uuid:$contentId})
+			RETURN coalesce(t.publishedDateTime = datetime($publishedDate), false) as matches`,
+		Params: map[string]interface{}{"contentId": contentID, "publishedDate": publishedDate},
+		Result: &results,
+	}
+
+	err := d.Read(qs)
+	a.NoError(err)
+
+	return results[0].Matches
+}
+
+func checkIsPostOfRelationship(d *cmneo4j.Driver, liveBlogID string, a *assert.Assertions) int {
+	countQuery := `
+		MATCH (x)-[r:IS_POST_OF]->(t:Thing{uuid:$liveBlogId})
+		RETURN count(r) as c`
+
+	var results []struct {
+		Count int `json:"c"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: countQuery,
+		Params: map[string]interface{}{"liveBlogId": liveBlogID},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].Count
+}
+
+func checkLinksToRelationship(d *cmneo4j.Driver, linkedID string, a *assert.Assertions) int {
+	countQuery := `
+		MATCH (t:Thing{uuid:$contentId})-[r:LINKS_TO]->(x:Thing{uuid:$linkedId})
+		RETURN count(r) as c`
+
+	var results []struct {
+		Count int `json:"c"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: countQuery,
+		Params: map[string]interface{}{"contentId": contentUUID, "linkedId": linkedID},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].Count
+}
+
+func embedPositions(d *cmneo4j.Driver, embeddedID string, a *assert.Assertions) []int {
+	var results []struct {
+		Position int `json:"position"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: `
+			MATCH (t:Thing{uuid:$contentId})-[r:EMBEDS]->(x:Thing{uuid:$embeddedId})
+			RETURN r.position as position`,
+		Params: map[string]interface{}{"contentId": contentUUID, "embeddedId": embeddedID},
+		Result: &results,
+	}
+
+	err := d.Read(qs)
+	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return nil
+	}
+	a.NoError(err)
+
+	var positions []int
+	for _, r := range results {
+		positions = append(positions, r.Position)
+	}
+	return positions
+}
+
+func markRelationships(d *cmneo4j.Driver, contentID string, a *assert.Assertions) {
+	markQuery := &cmneo4j.Query{
+		Cypher: `
+			MATCH (t:Thing{uuid:$contentId})-[r:IS_CURATED_FOR|CONTAINS]-(x)
+			SET r.marker = true`,
+		Params: map[string]interface{}{
+			"contentId": contentID,
+		},
+	}
+
+	err := d.Write(markQuery)
+	a.NoError(err)
+}
+
+func countMarkedRelationships(d *cmneo4j.Driver, contentID string, a *assert.Assertions) int {
+	countQuery := `
+		MATCH (t:Thing{uuid:$contentId})-[r:IS_CURATED_FOR|CONTAINS]-(x)
+		WHERE r.marker = true
+		RETURN count(r) as c`
+
+	var results []struct {
+		Count int `json:"c"`
+	}
+
+	qs := &cmneo4j.Query{
+		Cypher: countQuery,
+		Params: map[string]interface{}{"contentId": contentID},
+		Result: &results,
+	}
+
+	err := d.Write(qs)
+	a.NoError(err)
+
+	return results[0].Count
+}
+
+func checkDBClean(d *cmneo4j.Driver, t testing.TB) {
 	a := assert.New(t)
 
 	var result []struct {
@@ -1006,7 +2719,7 @@ func checkDBClean(d *cmneo4j.Driver, t *testing.T) {
 	}
 }
 
-func getAgent(p string, l *logger.UPPLogger, t *testing.T) policy.Agent {
+func getAgent(p string, l *logger.UPPLogger, t testing.TB) policy.Agent {
 	url := os.Getenv("OPA_URL")
 	if url == "" {
 		url = "http://localhost:8181"
@@ -1046,7 +2759,8 @@ func getAgent(p string, l *logger.UPPLogger, t *testing.T) policy.Agent {
 }
 
 func getContentService(d *cmneo4j.Driver, a policy.Agent, l *logger.UPPLogger) Service {
-	cs := NewContentService(d, a, l)
+	cs := NewContentService(d, a, testBatchSize, l)
 	_ = cs.Initialise()
+	_, _ = cs.MigrateSchema(time.Minute)
 	return cs
 }
@@ -0,0 +1,40 @@
+package content
+
+import (
+	"fmt"
+	"time"
+)
+
+// publishedDateFormat is the format dates are normalised to: RFC 3339 in UTC with milliseconds
+const publishedDateFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// publishedDateLayouts are the accepted formats of publishedDate and firstPublishedDate, tried in order:
+//   - RFC 3339, with or without fractional seconds, e.g. 2014-07-08T13:52:52.000Z or 2014-07-08T13:52:52+01:00
+//   - RFC 3339 with an offset without colon, e.g. 2014-07-08T13:52:52.000+0100
+//   - RFC 3339 without time zone, e.g. 2014-07-08T13:52:52.000, which is taken as UTC
+var publishedDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// parsePublishedDate parses a date in any of the publishedDateLayouts
+func parsePublishedDate(value string) (time.Time, error) {
+	for _, layout := range publishedDateLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid date, expected RFC 3339 e.g. 2014-07-08T13:52:52.000Z", value)
+}
+
+// normaliseDate returns the value of the date field in publishedDateFormat along with its epoch seconds.
+// The error is a *ValidationError when the date is not in any of the accepted formats.
+func normaliseDate(field string, value string) (string, int64, error) {
+	t, err := parsePublishedDate(value)
+	if err != nil {
+		return "", 0, newValidationError(FieldError{Field: field, Message: err.Error()})
+	}
+	return t.Format(publishedDateFormat), t.Unix(), nil
+}
@@ -0,0 +1,84 @@
+package content
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseDate(t *testing.T) {
+	tests := map[string]struct {
+		value         string
+		expectedDate  string
+		expectedEpoch int64
+	}{
+		"RFC 3339 with milliseconds in UTC": {
+			value:         "2014-07-08T13:52:52.000Z",
+			expectedDate:  "2014-07-08T13:52:52.000Z",
+			expectedEpoch: 1404827572,
+		},
+		"RFC 3339 without fractional seconds": {
+			value:         "2014-07-08T13:52:52Z",
+			expectedDate:  "2014-07-08T13:52:52.000Z",
+			expectedEpoch: 1404827572,
+		},
+		"RFC 3339 with nanoseconds": {
+			value:         "2014-07-08T13:52:52.123456789Z",
+			expectedDate:  "2014-07-08T13:52:52.123Z",
+			expectedEpoch: 1404827572,
+		},
+		"RFC 3339 with offset": {
+			value:         "2014-07-08T14:52:52.000+01:00",
+			expectedDate:  "2014-07-08T13:52:52.000Z",
+			expectedEpoch: 1404827572,
+		},
+		"offset without colon": {
+			value:         "2014-07-08T14:52:52.250+0100",
+			expectedDate:  "2014-07-08T13:52:52.250Z",
+			expectedEpoch: 1404827572,
+		},
+		"no time zone": {
+			value:         "2014-07-08T13:52:52.000",
+			expectedDate:  "2014-07-08T13:52:52.000Z",
+			expectedEpoch: 1404827572,
+		},
+		"no time zone nor fractional seconds": {
+			value:         "2014-07-08T13:52:52",
+			expectedDate:  "2014-07-08T13:52:52.000Z",
+			expectedEpoch: 1404827572,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			date, epoch, err := normaliseDate("publishedDate", test.value)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDate, date)
+			assert.Equal(t, test.expectedEpoch, epoch)
+		})
+	}
+}
+
+func TestNormaliseInvalidDate(t *testing.T) {
+	values := []string{
+		"not a date",
+		"2014-07-08",
+		"08/07/2014 13:52:52",
+		"2014-13-08T13:52:52.000Z",
+		"2014-07-08T13:52:52.000+25:00",
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			_, _, err := normaliseDate("firstPublishedDate", value)
+
+			var validationErr *ValidationError
+			assert.True(t, errors.As(err, &validationErr), "expected a validation error")
+			if assert.Len(t, validationErr.Errors, 1) {
+				assert.Equal(t, "firstPublishedDate", validationErr.Errors[0].Field)
+			}
+		})
+	}
+}
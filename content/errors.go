@@ -0,0 +1,53 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError describes why a field of the content is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when the content cannot be written because some of its fields are invalid
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func newValidationError(errs ...FieldError) *ValidationError {
+	return &ValidationError{Errors: errs}
+}
+
+// inItem returns the error with the fields prefixed by the given item of a batch, e.g. content[2].uuid
+func (e *ValidationError) inItem(item string) *ValidationError {
+	errs := make([]FieldError, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, FieldError{Field: item + "." + fe.Field, Message: fe.Message})
+	}
+	return newValidationError(errs...)
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return "invalid content: " + strings.Join(messages, "; ")
+}
+
+// typeFieldError returns the field error of a JSON value which cannot be decoded into the type of its field,
+// e.g. a string sent for a list
+func typeFieldError(err error) (FieldError, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return FieldError{}, false
+	}
+	return FieldError{
+		Field:   typeErr.Field,
+		Message: fmt.Sprintf("%s cannot be a JSON %s", typeErr.Field, typeErr.Value),
+	}, true
+}
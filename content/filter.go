@@ -0,0 +1,81 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contentFilter selects the content nodes listed or counted, its zero value selecting all of them
+type contentFilter struct {
+	// Type is a content type label, e.g. Article
+	Type string
+	// Publication is the UUID of a publication the content is published in
+	Publication string
+	// EditorialDesk is the path of the desk which published the content
+	EditorialDesk string
+	// PublishedFrom and PublishedTo bound publishedDateEpoch, both inclusively, when set
+	PublishedFrom *int64
+	PublishedTo   *int64
+}
+
+// validate checks the fields of the filter, it returns a *ValidationError listing all the invalid fields
+func (f contentFilter) validate() error {
+	var errs []FieldError
+
+	if f.Type != "" && !allowedTypes[f.Type] {
+		errs = append(errs, FieldError{Field: "type", Message: fmt.Sprintf("%q is not an allowed content type", f.Type)})
+	}
+
+	if f.Publication != "" && !uuidRegex.MatchString(f.Publication) {
+		errs = append(errs, FieldError{Field: "publication", Message: fmt.Sprintf("%q is not a valid UUID", f.Publication)})
+	}
+
+	if f.PublishedFrom != nil && f.PublishedTo != nil && *f.PublishedFrom > *f.PublishedTo {
+		errs = append(errs, FieldError{Field: "publishedFrom", Message: "publishedFrom must not be after publishedTo"})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return newValidationError(errs...)
+}
+
+// matchCypher matches the content nodes n selected by the filter, along with the params it uses.
+// The type is a label, which cannot be a param, so it must have been validated beforehand.
+func (f contentFilter) matchCypher() (string, map[string]interface{}) {
+	labels := ":Content"
+	if f.Type != "" && f.Type != "Content" {
+		labels += ":" + f.Type
+	}
+
+	var conditions []string
+	params := map[string]interface{}{}
+
+	// n.publication is only set on nodes which have not been migrated yet, see MigratePublications
+	if f.Publication != "" {
+		conditions = append(conditions, "((n)-[:PUBLISHED_IN]->(:Thing {uuid: $publication}) OR $publication IN coalesce(n.publication, []))")
+		params["publication"] = f.Publication
+	}
+
+	if f.EditorialDesk != "" {
+		conditions = append(conditions, "(n)-[:PUBLISHED_BY_DESK]->(:EditorialDesk {path: $editorialDesk})")
+		params["editorialDesk"] = normaliseEditorialDesk(f.EditorialDesk)
+	}
+
+	if f.PublishedFrom != nil {
+		conditions = append(conditions, "n.publishedDateEpoch >= $publishedFrom")
+		params["publishedFrom"] = *f.PublishedFrom
+	}
+
+	if f.PublishedTo != nil {
+		conditions = append(conditions, "n.publishedDateEpoch <= $publishedTo")
+		params["publishedTo"] = *f.PublishedTo
+	}
+
+	cypher := fmt.Sprintf("MATCH (n%s)", labels)
+	if len(conditions) > 0 {
+		cypher += `
+			WHERE ` + strings.Join(conditions, " AND ")
+	}
+	return cypher, params
+}
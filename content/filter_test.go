@@ -0,0 +1,106 @@
+package content
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatchCypher(t *testing.T) {
+	from := int64(1404777600)
+	to := int64(1404864000)
+
+	tests := map[string]struct {
+		filter         contentFilter
+		expectedCypher string
+		expectedParams map[string]interface{}
+	}{
+		"no filter": {
+			filter:         contentFilter{},
+			expectedCypher: "MATCH (n:Content)",
+			expectedParams: map[string]interface{}{},
+		},
+		"content type": {
+			filter:         contentFilter{Type: "Content"},
+			expectedCypher: "MATCH (n:Content)",
+			expectedParams: map[string]interface{}{},
+		},
+		"all filters": {
+			filter: contentFilter{
+				Type:          "Article",
+				Publication:   "8e6c705e-1132-42a2-8db0-c295e29e8658",
+				EditorialDesk: "FT/ Newsdesk/",
+				PublishedFrom: &from,
+				PublishedTo:   &to,
+			},
+			expectedCypher: `MATCH (n:Content:Article)
+			WHERE ((n)-[:PUBLISHED_IN]->(:Thing {uuid: $publication}) OR $publication IN coalesce(n.publication, [])) AND ` +
+				`(n)-[:PUBLISHED_BY_DESK]->(:EditorialDesk {path: $editorialDesk}) AND ` +
+				`n.publishedDateEpoch >= $publishedFrom AND n.publishedDateEpoch <= $publishedTo`,
+			expectedParams: map[string]interface{}{
+				"publication":   "8e6c705e-1132-42a2-8db0-c295e29e8658",
+				"editorialDesk": "/FT/Newsdesk",
+				"publishedFrom": from,
+				"publishedTo":   to,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cypher, params := test.filter.matchCypher()
+
+			assert.Equal(t, test.expectedCypher, cypher)
+			assert.Equal(t, test.expectedParams, params)
+		})
+	}
+}
+
+func TestFilterValidation(t *testing.T) {
+	from := int64(1404864000)
+	to := int64(1404777600)
+
+	err := contentFilter{
+		Type:          "Concept",
+		Publication:   "FTPink",
+		PublishedFrom: &from,
+		PublishedTo:   &to,
+	}.validate()
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []FieldError{
+			{Field: "type", Message: `"Concept" is not an allowed content type`},
+			{Field: "publication", Message: `"FTPink" is not a valid UUID`},
+			{Field: "publishedFrom", Message: "publishedFrom must not be after publishedTo"},
+		}, validationErr.Errors)
+	}
+
+	assert.NoError(t, contentFilter{Type: "Article", PublishedFrom: &to, PublishedTo: &from}.validate())
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	uuid, err := decodeCursor(encodeCursor("ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ce3f2f5e-33d1-4c36-89e3-51aa00fd5660", uuid)
+
+	uuid, err = decodeCursor("")
+	assert.NoError(t, err)
+	assert.Empty(t, uuid, "an empty cursor should start from the first page")
+
+	_, err = decodeCursor("not a cursor!")
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestListRejectsInvalidLimits(t *testing.T) {
+	for _, limit := range []int{-1, 0, maxPageSize + 1} {
+		_, err := Service{}.List(contentFilter{}, "", limit, "TEST_TRANS_ID")
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr), "limit %d should be rejected", limit) {
+			assert.Equal(t, "limit", validationErr.Errors[0].Field)
+		}
+	}
+}
@@ -0,0 +1,840 @@
+package content
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	transactionidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/gorilla/mux"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+
+	// DefaultMaxReadBatchSize is the maximum number of UUIDs read by a single batch read when none is configured
+	DefaultMaxReadBatchSize = 1000
+	// DefaultMaxDeleteBatchSize is the maximum number of UUIDs deleted by a single bulk delete when none is configured
+	DefaultMaxDeleteBatchSize = 1000
+	// DefaultMaxWriteBatchSize is the maximum number of content items written by a single bulk write when none is configured
+	DefaultMaxWriteBatchSize = 1000
+)
+
+// HandlerConfig holds the options of the content endpoints
+type HandlerConfig struct {
+	// RejectUnknownFields rejects the written content holding fields which are not part of the model
+	RejectUnknownFields bool
+	// CanonicaliseUUIDCase lower cases the content UUIDs of the path and of the body before using them,
+	// so that UUIDs which only differ by case identify the same content
+	CanonicaliseUUIDCase bool
+	// MaxReadBatchSize is the maximum number of UUIDs of a batch read, DefaultMaxReadBatchSize when not set
+	MaxReadBatchSize int
+	// MaxDeleteBatchSize is the maximum number of UUIDs of a bulk delete, DefaultMaxDeleteBatchSize when not set
+	MaxDeleteBatchSize int
+	// MaxWriteBatchSize is the maximum number of content items of a bulk write, DefaultMaxWriteBatchSize when not set
+	MaxWriteBatchSize int
+}
+
+// Handler serves the content endpoints: the standard read/write ones (PUT, GET and DELETE on
+// /content/{uuid} and /content/__count) and the content specific ones built on top of them.
+type Handler struct {
+	service Service
+	config  HandlerConfig
+	log     *logger.UPPLogger
+}
+
+// NewHandler creates a Handler for the given content service
+func NewHandler(s Service, c HandlerConfig, l *logger.UPPLogger) *Handler {
+	return &Handler{
+		service: s,
+		config:  c,
+		log:     l,
+	}
+}
+
+// RegisterHandlers registers the content endpoints on the router
+func (h *Handler) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc("/content/__count", h.countHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/__search", h.searchHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/__schema", h.schemaStatusHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/__placeholders/collect", h.collectPlaceholdersHandler).Methods(http.MethodPost)
+	r.HandleFunc("/content/__read", h.batchReadHandler).Methods(http.MethodPost)
+	r.HandleFunc("/content/__delete", h.batchDeleteHandler).Methods(http.MethodPost)
+	r.HandleFunc("/content/__write", h.batchWriteHandler).Methods(http.MethodPost)
+	r.HandleFunc("/content", h.listHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/{uuid}/members", h.membersHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/{uuid}/storyPackages/{packageUUID}", h.putPackageHandler(h.service.AddStoryPackage)).Methods(http.MethodPut)
+	r.HandleFunc("/content/{uuid}/storyPackages/{packageUUID}", h.deletePackageHandler(h.service.RemoveStoryPackage)).Methods(http.MethodDelete)
+	r.HandleFunc("/content/{uuid}/contentPackages/{packageUUID}", h.putPackageHandler(h.service.AddContentPackage)).Methods(http.MethodPut)
+	r.HandleFunc("/content/{uuid}/contentPackages/{packageUUID}", h.deletePackageHandler(h.service.RemoveContentPackage)).Methods(http.MethodDelete)
+	r.HandleFunc("/content/{uuid}", h.getHandler).Methods(http.MethodGet)
+	r.HandleFunc("/content/{uuid}", h.putHandler).Methods(http.MethodPut)
+	r.HandleFunc("/content/{uuid}", h.patchHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/content/{uuid}", h.deleteHandler).Methods(http.MethodDelete)
+}
+
+func (h *Handler) putHandler(w http.ResponseWriter, r *http.Request) {
+	uuid := h.pathUUID(r)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	body, err := requestBody(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	if h.config.RejectUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	inst, _, err := h.service.DecodeJSON(dec)
+	if field, ok := unknownField(err); ok {
+		writeValidationError(w, newValidationError(FieldError{Field: field, Message: "unknown field"}))
+		return
+	}
+	if fieldErr, ok := typeFieldError(err); ok {
+		writeValidationError(w, newValidationError(fieldErr))
+		return
+	}
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c := inst.(content)
+	if h.config.CanonicaliseUUIDCase {
+		c.UUID = strings.ToLower(c.UUID)
+	}
+
+	if c.UUID != uuid {
+		writeValidationError(w, newValidationError(FieldError{
+			Field:   "uuid",
+			Message: fmt.Sprintf("uuid %q of the body does not match uuid %q of the path", c.UUID, uuid),
+		}))
+		return
+	}
+
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	err = h.service.Write(c, tid)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		h.log.WithTransactionID(tid).WithUUID(uuid).WithError(err).Error("Failed to write content")
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("X-Request-Id", tid)
+	writeJSONMessage(w, "PUT successful", http.StatusOK)
+}
+
+func (h *Handler) patchHandler(w http.ResponseWriter, r *http.Request) {
+	uuid := h.pathUUID(r)
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		writeJSONMessage(w, "patches must be sent as application/merge-patch+json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := requestBody(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.config.RejectUnknownFields {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&content{})
+		if field, ok := unknownField(err); ok {
+			writeValidationError(w, newValidationError(FieldError{Field: field, Message: "unknown field"}))
+			return
+		}
+	}
+
+	var patch map[string]interface{}
+	if err = json.Unmarshal(b, &patch); err != nil || patch == nil {
+		writeJSONMessage(w, "the patch must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	if patchUUID, ok := patch["uuid"].(string); ok && h.config.CanonicaliseUUIDCase {
+		patch["uuid"] = strings.ToLower(patchUUID)
+	}
+
+	found, err := h.service.Patch(uuid, patch, tid)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, ErrPatchConflict) {
+		writeJSONMessage(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, ErrPatchNotWritten) {
+		writeJSONMessage(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.log.WithTransactionID(tid).WithUUID(uuid).WithError(err).Error("Failed to patch content")
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if !found {
+		writeJSONMessage(w, fmt.Sprintf("content with uuid %s not found", uuid), http.StatusNotFound)
+		return
+	}
+
+	writeJSONMessage(w, "PATCH successful", http.StatusOK)
+}
+
+func (h *Handler) getHandler(w http.ResponseWriter, r *http.Request) {
+	uuid := h.pathUUID(r)
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	includeEmbeds, err := boolParam(r, "includeEmbeds")
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c, found, err := h.service.ReadWithOptions(uuid, tid, ReadOptions{IncludeEmbeds: includeEmbeds})
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, c, http.StatusOK)
+}
+
+func (h *Handler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	uuid := h.pathUUID(r)
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	deleted, err := h.service.Delete(uuid, tid)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("X-Request-Id", tid)
+
+	if deleted {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *Handler) countHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	filter, err := filterParams(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var count interface{}
+	if groupBy := r.URL.Query().Get("groupBy"); groupBy != "" {
+		count, err = h.service.CountBy(groupBy, filter)
+	} else {
+		count, err = h.service.CountFiltered(filter)
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, count, http.StatusOK)
+}
+
+func (h *Handler) searchHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeJSONMessage(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.Search(q, r.URL.Query().Get("type"), offset, limit)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, page, http.StatusOK)
+}
+
+func (h *Handler) schemaStatusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	status, err := h.service.SchemaStatus()
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, status, http.StatusOK)
+}
+
+func (h *Handler) collectPlaceholdersHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	dryRun, err := boolParam(r, "dryRun")
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.CollectPlaceholders(dryRun)
+	if errors.Is(err, ErrPlaceholderCollectionLocked) {
+		writeJSONMessage(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.log.WithTransactionID(tid).WithError(err).Errorf("Failed to collect orphaned placeholders after deleting %d", report.Count)
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, report, http.StatusOK)
+}
+
+func (h *Handler) membersHandler(w http.ResponseWriter, r *http.Request) {
+	uuid := h.pathUUID(r)
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, found, err := h.service.Members(uuid, offset, limit)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if !found {
+		writeJSONMessage(w, fmt.Sprintf("content with uuid %s not found", uuid), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, page, http.StatusOK)
+}
+
+// batchRequest is the body of the batch read and bulk delete requests
+type batchRequest struct {
+	UUIDs []string `json:"uuids"`
+}
+
+// batchWriteRequest is the body of a bulk write, each item being decoded as the body of a PUT
+type batchWriteRequest struct {
+	Content []json.RawMessage `json:"content"`
+}
+
+func (h *Handler) listHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	filter, err := filterParams(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intParam(r, "limit", defaultPageSize)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit < 1 || limit > maxPageSize {
+		writeJSONMessage(w, fmt.Sprintf("limit must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.List(filter, r.URL.Query().Get("cursor"), limit, tid)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, page, http.StatusOK)
+}
+
+func (h *Handler) batchReadHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	includeEmbeds, err := boolParam(r, "includeEmbeds")
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	maxSize := h.config.MaxReadBatchSize
+	if maxSize < 1 {
+		maxSize = DefaultMaxReadBatchSize
+	}
+
+	requested, ok := h.batchUUIDs(w, r, maxSize, "read")
+	if !ok {
+		return
+	}
+
+	uuids, err := h.requestUUIDs(requested)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+
+	batch, err := h.service.ReadBatch(uuids, tid, ReadOptions{IncludeEmbeds: includeEmbeds})
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, batch, http.StatusOK)
+}
+
+func (h *Handler) batchDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	maxSize := h.config.MaxDeleteBatchSize
+	if maxSize < 1 {
+		maxSize = DefaultMaxDeleteBatchSize
+	}
+
+	requested, ok := h.batchUUIDs(w, r, maxSize, "deleted")
+	if !ok {
+		return
+	}
+	if len(requested) == 0 {
+		writeJSONMessage(w, "at least one uuid must be deleted", http.StatusBadRequest)
+		return
+	}
+
+	// malformed UUIDs are reported as failed by DeleteBatch, the valid ones still being deleted
+	uuids := make([]string, 0, len(requested))
+	for _, uuid := range requested {
+		uuids = append(uuids, h.canonicalUUID(uuid))
+	}
+
+	writeJSON(w, map[string]interface{}{"results": h.service.DeleteBatch(uuids, tid)}, http.StatusOK)
+}
+
+func (h *Handler) batchWriteHandler(w http.ResponseWriter, r *http.Request) {
+	tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", tid)
+
+	maxSize := h.config.MaxWriteBatchSize
+	if maxSize < 1 {
+		maxSize = DefaultMaxWriteBatchSize
+	}
+
+	body, err := requestBody(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	var req batchWriteRequest
+	if err = json.NewDecoder(body).Decode(&req); err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Content) > maxSize {
+		writeJSONMessage(w, fmt.Sprintf("at most %d content items can be written at once", maxSize), http.StatusBadRequest)
+		return
+	}
+
+	batch := make([]interface{}, 0, len(req.Content))
+	var errs []FieldError
+	for i, raw := range req.Content {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if h.config.RejectUnknownFields {
+			dec.DisallowUnknownFields()
+		}
+
+		inst, _, err := h.service.DecodeJSON(dec)
+		if field, ok := unknownField(err); ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("content[%d].%s", i, field), Message: "unknown field"})
+			continue
+		}
+		if fieldErr, ok := typeFieldError(err); ok {
+			errs = append(errs, newValidationError(fieldErr).inItem(fmt.Sprintf("content[%d]", i)).Errors...)
+			continue
+		}
+		if err != nil {
+			writeJSONMessage(w, fmt.Sprintf("content[%d]: %s", i, err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		c := inst.(content)
+		if h.config.CanonicaliseUUIDCase {
+			c.UUID = strings.ToLower(c.UUID)
+		}
+		batch = append(batch, c)
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, newValidationError(errs...))
+		return
+	}
+
+	err = h.service.WriteBatch(batch, tid)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		h.log.WithTransactionID(tid).WithError(err).Error("Failed to write content batch")
+		writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSONMessage(w, "POST successful", http.StatusOK)
+}
+
+// batchUUIDs reads the UUIDs of a batch request, responding with a 400 when the body is invalid or lists
+// more than maxSize UUIDs
+// or lists more than maxSize UUIDs
+func (h *Handler) batchUUIDs(w http.ResponseWriter, r *http.Request, maxSize int, action string) ([]string, bool) {
+	body, err := requestBody(r)
+	if err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	defer body.Close()
+
+	var req batchRequest
+	if err = json.NewDecoder(body).Decode(&req); err != nil {
+		writeJSONMessage(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if len(req.UUIDs) > maxSize {
+		writeJSONMessage(w, fmt.Sprintf("at most %d uuids can be %s at once", maxSize, action), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return req.UUIDs, true
+}
+
+// requestUUIDs checks the content UUIDs listed in the body of a request and canonicalises them when configured to
+func (h *Handler) requestUUIDs(uuids []string) ([]string, error) {
+	var errs []FieldError
+	canonical := make([]string, 0, len(uuids))
+	for i, uuid := range uuids {
+		if !uuidRegex.MatchString(uuid) {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("uuids[%d]", i), Message: fmt.Sprintf("%q is not a valid UUID", uuid)})
+			continue
+		}
+		canonical = append(canonical, h.canonicalUUID(uuid))
+	}
+
+	if len(errs) > 0 {
+		return nil, newValidationError(errs...)
+	}
+	return canonical, nil
+}
+
+// canonicalUUID lower cases the UUID listed in the body of a request when configured to
+func (h *Handler) canonicalUUID(uuid string) string {
+	if h.config.CanonicaliseUUIDCase {
+		return strings.ToLower(uuid)
+	}
+	return uuid
+}
+
+// packageRelationFunc adds or removes the relationship between content and one of its packages
+type packageRelationFunc func(uuid string, packageUUID string, transID string) (bool, error)
+
+func (h *Handler) putPackageHandler(add packageRelationFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uuid := h.pathUUID(r)
+		packageUUID := h.pathPackageUUID(r)
+		tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", tid)
+
+		found, err := add(uuid, packageUUID, tid)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			writeValidationError(w, validationErr)
+			return
+		}
+		if err != nil {
+			h.log.WithTransactionID(tid).WithUUID(uuid).WithError(err).Error("Failed to add package relationship")
+			writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		if !found {
+			writeJSONMessage(w, fmt.Sprintf("content with uuid %s not found", uuid), http.StatusNotFound)
+			return
+		}
+
+		writeJSONMessage(w, "PUT successful", http.StatusOK)
+	}
+}
+
+func (h *Handler) deletePackageHandler(remove packageRelationFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uuid := h.pathUUID(r)
+		packageUUID := h.pathPackageUUID(r)
+		tid := transactionidutils.GetTransactionIDFromRequest(r)
+
+		w.Header().Set("X-Request-Id", tid)
+
+		removed, err := remove(uuid, packageUUID, tid)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			w.Header().Add("Content-Type", "application/json")
+			writeValidationError(w, validationErr)
+			return
+		}
+		if err != nil {
+			h.log.WithTransactionID(tid).WithUUID(uuid).WithError(err).Error("Failed to remove package relationship")
+			w.Header().Add("Content-Type", "application/json")
+			writeJSONMessage(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		if removed {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// requestBody returns the body of the request, decompressed when it is gzipped
+func requestBody(r *http.Request) (io.ReadCloser, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return r.Body, nil
+	}
+	return gzip.NewReader(r.Body)
+}
+
+// pathUUID returns the content UUID of the path, canonicalised when configured to
+func (h *Handler) pathUUID(r *http.Request) string {
+	uuid := mux.Vars(r)["uuid"]
+	if h.config.CanonicaliseUUIDCase {
+		return strings.ToLower(uuid)
+	}
+	return uuid
+}
+
+// pathPackageUUID returns the package UUID of the path, canonicalised when configured to
+func (h *Handler) pathPackageUUID(r *http.Request) string {
+	packageUUID := mux.Vars(r)["packageUUID"]
+	if h.config.CanonicaliseUUIDCase {
+		return strings.ToLower(packageUUID)
+	}
+	return packageUUID
+}
+
+// pageParams reads the offset and limit query parameters, applying the defaults when they are missing
+func pageParams(r *http.Request) (int, int, error) {
+	offset, err := intParam(r, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+
+	limit, err := intParam(r, "limit", defaultPageSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit < 1 || limit > maxPageSize {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+
+	return offset, limit, nil
+}
+
+// filterParams reads the content filter from the type, publication, editorialDesk, publishedFrom
+// and publishedTo query parameters, the latter being epoch seconds
+func filterParams(r *http.Request) (contentFilter, error) {
+	query := r.URL.Query()
+	filter := contentFilter{
+		Type:          query.Get("type"),
+		Publication:   query.Get("publication"),
+		EditorialDesk: query.Get("editorialDesk"),
+	}
+
+	var err error
+	if filter.PublishedFrom, err = int64Param(r, "publishedFrom"); err != nil {
+		return contentFilter{}, err
+	}
+	if filter.PublishedTo, err = int64Param(r, "publishedTo"); err != nil {
+		return contentFilter{}, err
+	}
+	return filter, nil
+}
+
+func intParam(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return i, nil
+}
+
+// int64Param returns nil when the query parameter is missing
+func int64Param(r *http.Request, name string) (*int64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &i, nil
+}
+
+func boolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", name)
+	}
+	return b, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, statusCode int) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "{\"message\": %q}", err.Error())
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(b)
+}
+
+func writeJSONMessage(w http.ResponseWriter, msg string, statusCode int) {
+	writeJSON(w, map[string]string{"message": msg}, statusCode)
+}
+
+// unknownField returns the name of the unknown field the decoding error is about, if any.
+// encoding/json does not export a type for these errors, so they are recognised by their message.
+func unknownField(err error) (string, bool) {
+	const prefix = `json: unknown field "`
+	if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(err.Error(), prefix), `"`), true
+}
+
+// writeValidationError responds with a 400 listing the invalid fields
+func writeValidationError(w http.ResponseWriter, err *ValidationError) {
+	writeJSON(w, struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}{
+		Message: err.Error(),
+		Errors:  err.Errors,
+	}, http.StatusBadRequest)
+}
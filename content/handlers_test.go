@@ -0,0 +1,542 @@
+package content
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+func TestMembersHandlerRejectsInvalidPageParams(t *testing.T) {
+	tests := map[string]struct {
+		query           string
+		expectedMessage string
+	}{
+		"offset is not a number": {
+			query:           "offset=first",
+			expectedMessage: `{"message":"offset must be an integer"}`,
+		},
+		"negative offset": {
+			query:           "offset=-1",
+			expectedMessage: `{"message":"offset must not be negative"}`,
+		},
+		"limit is not a number": {
+			query:           "limit=all",
+			expectedMessage: `{"message":"limit must be an integer"}`,
+		},
+		"zero limit": {
+			query:           "limit=0",
+			expectedMessage: `{"message":"limit must be between 1 and 500"}`,
+		},
+		"limit above the maximum": {
+			query:           "limit=501",
+			expectedMessage: `{"message":"limit must be between 1 and 500"}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/content/"+"1520b6b9-d466-49a0-b3ec-894b72338e7d"+"/members?"+test.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestPageParamsDefaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/content/"+"1520b6b9-d466-49a0-b3ec-894b72338e7d"+"/members", nil)
+
+	offset, limit, err := pageParams(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, offset)
+	assert.Equal(t, defaultPageSize, limit)
+}
+
+func TestGetHandlerRejectsInvalidIncludeEmbeds(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d?includeEmbeds=maybe", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"includeEmbeds must be a boolean"}`, rec.Body.String())
+}
+
+func TestPutHandlerRejectsInvalidPublishedDate(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	// Video content can be written without a body, so the date is validated before anything else is used
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Video","publishedDate":"08/07/2014"}`
+	req := httptest.NewRequest(http.MethodPut, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"publishedDate"`)
+}
+
+func TestPutHandlerRejectsUnknownFields(t *testing.T) {
+	router := mux.NewRouter()
+	config := HandlerConfig{RejectUnknownFields: true}
+	NewHandler(Service{}, config, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Video","headline":"Unknown"}`
+	req := httptest.NewRequest(http.MethodPut, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "invalid content: headline: unknown field",
+		"errors": [{"field": "headline", "message": "unknown field"}]
+	}`, rec.Body.String())
+}
+
+func TestPutHandlerListsAllInvalidFields(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Video","storyPackage":"sp","contentPackage":"1520b6b9-d466-49a0-b3ec-894b72338e7d"}`
+	req := httptest.NewRequest(http.MethodPut, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"storyPackage"`)
+	assert.Contains(t, rec.Body.String(), `"field":"contentPackage"`)
+}
+
+func TestPutHandlerRejectsWronglyTypedFields(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Video","publication":"x"}`
+	req := httptest.NewRequest(http.MethodPut, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "invalid content: publication: publication cannot be a JSON string",
+		"errors": [{"field": "publication", "message": "publication cannot be a JSON string"}]
+	}`, rec.Body.String())
+}
+
+func TestPutHandlerSkipsContentWhichIsNotWrittenWithoutValidatingIt(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	// Image content without a body is not written, so it is not validated either
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Image","storyPackage":"sp"}`
+	req := httptest.NewRequest(http.MethodPut, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPutHandlerChecksPathAndBodyUUIDsMatch(t *testing.T) {
+	// Image content without a body is not written, so only the UUIDs are checked
+	payload := `{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d","type":"Image"}`
+
+	tests := map[string]struct {
+		config          HandlerConfig
+		path            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		"matching uuids": {
+			path:            "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d",
+			expectedStatus:  http.StatusOK,
+			expectedMessage: `{"message":"PUT successful"}`,
+		},
+		"different uuids": {
+			path:           "/content/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660",
+			expectedStatus: http.StatusBadRequest,
+			expectedMessage: `{
+				"message": "invalid content: uuid: uuid \"1520b6b9-d466-49a0-b3ec-894b72338e7d\" of the body does not match uuid \"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660\" of the path",
+				"errors": [{
+					"field": "uuid",
+					"message": "uuid \"1520b6b9-d466-49a0-b3ec-894b72338e7d\" of the body does not match uuid \"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660\" of the path"
+				}]
+			}`,
+		},
+		"uuids differing by case": {
+			path:           "/content/1520B6B9-D466-49A0-B3EC-894B72338E7D",
+			expectedStatus: http.StatusBadRequest,
+			expectedMessage: `{
+				"message": "invalid content: uuid: uuid \"1520b6b9-d466-49a0-b3ec-894b72338e7d\" of the body does not match uuid \"1520B6B9-D466-49A0-B3EC-894B72338E7D\" of the path",
+				"errors": [{
+					"field": "uuid",
+					"message": "uuid \"1520b6b9-d466-49a0-b3ec-894b72338e7d\" of the body does not match uuid \"1520B6B9-D466-49A0-B3EC-894B72338E7D\" of the path"
+				}]
+			}`,
+		},
+		"uuids differing by case when canonicalised": {
+			config:          HandlerConfig{CanonicaliseUUIDCase: true},
+			path:            "/content/1520B6B9-D466-49A0-B3EC-894B72338E7D",
+			expectedStatus:  http.StatusOK,
+			expectedMessage: `{"message":"PUT successful"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := mux.NewRouter()
+			NewHandler(Service{}, test.config, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+			req := httptest.NewRequest(http.MethodPut, test.path, strings.NewReader(payload))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestPatchHandlerRejectsInvalidPatches(t *testing.T) {
+	tests := map[string]struct {
+		contentType     string
+		patch           string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		"unsupported media type": {
+			contentType:     "text/plain",
+			patch:           `{"title":"New title"}`,
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			expectedMessage: `{"message":"patches must be sent as application/merge-patch+json"}`,
+		},
+		"not a JSON object": {
+			contentType:     "application/merge-patch+json",
+			patch:           `["title"]`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: `{"message":"the patch must be a JSON object"}`,
+		},
+		"null patch": {
+			contentType:     "application/merge-patch+json",
+			patch:           `null`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: `{"message":"the patch must be a JSON object"}`,
+		},
+		"unknown field": {
+			contentType:    "application/merge-patch+json; charset=utf-8",
+			patch:          `{"headline":"New headline"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedMessage: `{
+				"message": "invalid content: headline: unknown field",
+				"errors": [{"field": "headline", "message": "unknown field"}]
+			}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	config := HandlerConfig{RejectUnknownFields: true}
+	NewHandler(Service{}, config, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/content/1520b6b9-d466-49a0-b3ec-894b72338e7d", strings.NewReader(test.patch))
+			req.Header.Set("Content-Type", test.contentType)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestPackageHandlersRejectInvalidPackageUUIDs(t *testing.T) {
+	tests := map[string]struct {
+		method          string
+		path            string
+		expectedMessage string
+	}{
+		"invalid story package": {
+			method: http.MethodPut,
+			path:   "/content/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660/storyPackages/not-a-uuid",
+			expectedMessage: `{
+				"message": "invalid content: storyPackage: \"not-a-uuid\" is not a valid UUID",
+				"errors": [{"field": "storyPackage", "message": "\"not-a-uuid\" is not a valid UUID"}]
+			}`,
+		},
+		"self curating story package": {
+			method: http.MethodDelete,
+			path:   "/content/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660/storyPackages/CE3F2F5E-33D1-4C36-89E3-51AA00FD5660",
+			expectedMessage: `{
+				"message": "invalid content: storyPackage: content cannot refer to itself",
+				"errors": [{"field": "storyPackage", "message": "content cannot refer to itself"}]
+			}`,
+		},
+		"invalid content package": {
+			method: http.MethodDelete,
+			path:   "/content/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660/contentPackages/not-a-uuid",
+			expectedMessage: `{
+				"message": "invalid content: contentPackage: \"not-a-uuid\" is not a valid UUID",
+				"errors": [{"field": "contentPackage", "message": "\"not-a-uuid\" is not a valid UUID"}]
+			}`,
+		},
+		"self contained content package": {
+			method: http.MethodPut,
+			path:   "/content/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660/contentPackages/ce3f2f5e-33d1-4c36-89e3-51aa00fd5660",
+			expectedMessage: `{
+				"message": "invalid content: contentPackage: content cannot refer to itself",
+				"errors": [{"field": "contentPackage", "message": "content cannot refer to itself"}]
+			}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestBatchHandlersRejectInvalidRequests(t *testing.T) {
+	tests := map[string]struct {
+		path            string
+		body            string
+		expectedMessage string
+	}{
+		"not JSON": {
+			path:            "/content/__read",
+			body:            `uuids`,
+			expectedMessage: `{"message":"invalid character 'u' looking for beginning of value"}`,
+		},
+		"too many uuids to read": {
+			path:            "/content/__read",
+			body:            `{"uuids":["ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","1520b6b9-d466-49a0-b3ec-894b72338e7d","3b08c76c-7479-461d-9f0e-a4e92dca56f7"]}`,
+			expectedMessage: `{"message":"at most 2 uuids can be read at once"}`,
+		},
+		"too many uuids to delete": {
+			path:            "/content/__delete",
+			body:            `{"uuids":["ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","1520b6b9-d466-49a0-b3ec-894b72338e7d"]}`,
+			expectedMessage: `{"message":"at most 1 uuids can be deleted at once"}`,
+		},
+		"no uuids to delete": {
+			path:            "/content/__delete",
+			body:            `{"uuids":[]}`,
+			expectedMessage: `{"message":"at least one uuid must be deleted"}`,
+		},
+		"invalid uuids": {
+			path: "/content/__read",
+			body: `{"uuids":["ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","not-a-uuid"]}`,
+			expectedMessage: `{
+				"message": "invalid content: uuids[1]: \"not-a-uuid\" is not a valid UUID",
+				"errors": [{"field": "uuids[1]", "message": "\"not-a-uuid\" is not a valid UUID"}]
+			}`,
+		},
+		"too many content items to write": {
+			path:            "/content/__write",
+			body:            `{"content":[{"uuid":"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"},{"uuid":"1520b6b9-d466-49a0-b3ec-894b72338e7d"},{"uuid":"3b08c76c-7479-461d-9f0e-a4e92dca56f7"}]}`,
+			expectedMessage: `{"message":"at most 2 content items can be written at once"}`,
+		},
+		"unknown fields of written content": {
+			path: "/content/__write",
+			body: `{"content":[{"uuid":"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","colour":"pink"}]}`,
+			expectedMessage: `{
+				"message": "invalid content: content[0].colour: unknown field",
+				"errors": [{"field": "content[0].colour", "message": "unknown field"}]
+			}`,
+		},
+		"wrongly typed fields of written content": {
+			path: "/content/__write",
+			body: `{"content":[{"uuid":"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","storyPackages":"sp"}]}`,
+			expectedMessage: `{
+				"message": "invalid content: content[0].storyPackages: storyPackages cannot be a JSON string",
+				"errors": [{"field": "content[0].storyPackages", "message": "storyPackages cannot be a JSON string"}]
+			}`,
+		},
+		"invalid written content": {
+			path: "/content/__write",
+			body: `{"content":[{"uuid":"not-a-uuid","type":"Article"},{"uuid":"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660","type":"Article","storyPackage":"ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"}]}`,
+			expectedMessage: `{
+				"message": "invalid content: content[0].uuid: \"not-a-uuid\" is not a valid UUID; content[1].storyPackage: content cannot refer to itself",
+				"errors": [
+					{"field": "content[0].uuid", "message": "\"not-a-uuid\" is not a valid UUID"},
+					{"field": "content[1].storyPackage", "message": "content cannot refer to itself"}
+				]
+			}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	config := HandlerConfig{RejectUnknownFields: true, MaxReadBatchSize: 2, MaxDeleteBatchSize: 1, MaxWriteBatchSize: 2}
+	NewHandler(Service{}, config, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestBatchDeleteHandlerReportsMalformedUUIDsAsFailed(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/content/__delete", strings.NewReader(`{"uuids":["not-a-uuid","sp"]}`))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results": [
+		{"uuid": "not-a-uuid", "status": "failed", "error": "\"not-a-uuid\" is not a valid UUID"},
+		{"uuid": "sp", "status": "failed", "error": "\"sp\" is not a valid UUID"}
+	]}`, rec.Body.String())
+}
+
+func TestListHandlerRejectsInvalidParams(t *testing.T) {
+	tests := map[string]struct {
+		query           string
+		expectedMessage string
+	}{
+		"invalid publishedFrom": {
+			query:           "publishedFrom=yesterday",
+			expectedMessage: `{"message":"publishedFrom must be an integer"}`,
+		},
+		"invalid limit": {
+			query:           "limit=0",
+			expectedMessage: `{"message":"limit must be between 1 and 500"}`,
+		},
+		"invalid type": {
+			query: "type=Concept",
+			expectedMessage: `{
+				"message": "invalid content: type: \"Concept\" is not an allowed content type",
+				"errors": [{"field": "type", "message": "\"Concept\" is not an allowed content type"}]
+			}`,
+		},
+		"invalid cursor": {
+			query: "cursor=not%20a%20cursor",
+			expectedMessage: `{
+				"message": "invalid content: cursor: \"not a cursor\" is not a valid cursor",
+				"errors": [{"field": "cursor", "message": "\"not a cursor\" is not a valid cursor"}]
+			}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/content?"+test.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestCountHandlerRejectsInvalidGroupings(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/content/__count?groupBy=desk", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "invalid content: groupBy: \"desk\" is not a valid grouping, expected type, publication or month",
+		"errors": [{"field": "groupBy", "message": "\"desk\" is not a valid grouping, expected type, publication or month"}]
+	}`, rec.Body.String())
+}
+
+func TestSearchHandlerRejectsInvalidParams(t *testing.T) {
+	tests := map[string]struct {
+		query           string
+		expectedMessage string
+	}{
+		"missing query": {
+			query:           "q=%20",
+			expectedMessage: `{"message":"q is required"}`,
+		},
+		"invalid limit": {
+			query:           "q=bank&limit=1000",
+			expectedMessage: `{"message":"limit must be between 1 and 500"}`,
+		},
+		"invalid type": {
+			query: "q=bank&type=Concept",
+			expectedMessage: `{
+				"message": "invalid content: type: \"Concept\" is not an allowed content type",
+				"errors": [{"field": "type", "message": "\"Concept\" is not an allowed content type"}]
+			}`,
+		},
+	}
+
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/content/__search?"+test.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, test.expectedMessage, rec.Body.String())
+		})
+	}
+}
+
+func TestCollectPlaceholdersHandlerRejectsInvalidDryRun(t *testing.T) {
+	router := mux.NewRouter()
+	NewHandler(Service{}, HandlerConfig{}, logger.NewUPPLogger("content-rw-neo4j-test", "PANIC")).RegisterHandlers(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/content/__placeholders/collect?dryRun=maybe", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"dryRun must be a boolean"}`, rec.Body.String())
+}
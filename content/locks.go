@@ -0,0 +1,84 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// lock is held by a single instance at a time, on the node of its label with its name
+type lock struct {
+	label string
+	name  string
+	// ttl is how long the lock is held at most, so that it is released when its owner dies while holding it
+	ttl time.Duration
+}
+
+var (
+	// schemaLock is held while migrating the schema
+	schemaLock = lock{label: "SchemaMigrationLock", name: schemaLockName, ttl: 10 * time.Minute}
+	// placeholderCollectionLock is held while deleting the orphaned placeholders
+	placeholderCollectionLock = lock{label: "PlaceholderCollectionLock", name: "placeholders", ttl: 30 * time.Minute}
+)
+
+// take takes the lock when it is free, expired or already held by the owner, returning whether it was taken.
+// The lock node is written before its owner is checked, so that the write lock of the node is held by the
+// transaction and concurrent owners check it one after the other.
+func (l lock) take(driver *cmneo4j.Driver, owner string) (bool, error) {
+	var results []struct {
+		Owner string `json:"owner"`
+	}
+
+	err := driver.Write(&cmneo4j.Query{
+		Cypher: fmt.Sprintf(`MERGE (l:%s {name: $name})
+			SET l.attempt = timestamp()
+			WITH l
+			WHERE l.lockedUntil IS NULL OR l.lockedUntil < timestamp() OR l.owner = $owner
+			SET l.owner = $owner, l.lockedUntil = timestamp() + $ttl
+			RETURN l.owner AS owner`, l.label),
+		Params: map[string]interface{}{
+			"name":  l.name,
+			"owner": owner,
+			"ttl":   l.ttl.Milliseconds(),
+		},
+		Result: &results,
+	})
+	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(results) > 0, nil
+}
+
+// release releases the lock when it is held by the owner
+func (l lock) release(driver *cmneo4j.Driver, owner string) error {
+	return driver.Write(&cmneo4j.Query{
+		Cypher: fmt.Sprintf(`MATCH (l:%s {name: $name, owner: $owner})
+			REMOVE l.owner, l.lockedUntil`, l.label),
+		Params: map[string]interface{}{
+			"name":  l.name,
+			"owner": owner,
+		},
+	})
+}
+
+// releaseLock releases the lock held by the owner, which otherwise expires once its ttl has elapsed
+func (cd Service) releaseLock(l lock, owner string) {
+	if err := l.release(cd.driver, owner); err != nil {
+		cd.log.WithError(err).Warnf("Could not release the %s lock, it expires within %s", l.name, l.ttl)
+	}
+}
+
+// lockOwner identifies the instance holding a lock
+func lockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}
@@ -0,0 +1,75 @@
+package content
+
+import (
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// MigratePublications converts the publication array property of the content nodes written before
+// publications were modelled as nodes into PUBLISHED_IN relationships to Publication nodes.
+// Nodes are migrated in batches of batchSize, it returns the number of migrated nodes.
+func (cd Service) MigratePublications() (int, error) {
+	return cd.migrateInBatches(`
+		MATCH (n:Content) WHERE n.publication IS NOT NULL
+		WITH n LIMIT $batchSize
+		FOREACH (position IN range(0, size(n.publication) - 1) |
+			MERGE (p:Thing {uuid: n.publication[position]})
+			SET p:Publication
+			MERGE (n)-[rel:PUBLISHED_IN]->(p)
+			SET rel.position = position)
+		REMOVE n.publication
+		RETURN count(n) as c`)
+}
+
+// MigratePublishedDateTime sets the native datetime publishedDateTime property of the content nodes
+// written before it was introduced, from their publishedDate.
+// Nodes are migrated in batches of batchSize, it returns the number of migrated nodes.
+func (cd Service) MigratePublishedDateTime() (int, error) {
+	return cd.migrateInBatches(`
+		MATCH (n:Content) WHERE n.publishedDate IS NOT NULL AND n.publishedDateTime IS NULL
+		WITH n LIMIT $batchSize
+		SET n.publishedDateTime = datetime(n.publishedDate)
+		RETURN count(n) as c`)
+}
+
+// migrateInBatches runs the migration query until it reports that no more nodes were migrated.
+// The query must limit the number of nodes it migrates to $batchSize and return their count as c.
+func (cd Service) migrateInBatches(cypher string) (int, error) {
+	return cd.runInBatches(cypher, "Migrated %d content nodes so far")
+}
+
+// runInBatches runs the query until it reports that no more nodes were changed, logging the progress
+// with the format after each batch. The query must limit the number of nodes it changes to $batchSize
+// and return their count as c.
+func (cd Service) runInBatches(cypher string, progressFormat string) (int, error) {
+	batchSize := cd.batchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	total := 0
+	for {
+		var results []struct {
+			Count int `json:"c"`
+		}
+
+		query := &cmneo4j.Query{
+			Cypher: cypher,
+			Params: map[string]interface{}{
+				"batchSize": batchSize,
+			},
+			Result: &results,
+		}
+
+		err := cd.driver.Write(query)
+		if err != nil {
+			return total, err
+		}
+
+		if len(results) == 0 || results[0].Count == 0 {
+			return total, nil
+		}
+
+		total += results[0].Count
+		cd.log.Infof(progressFormat, total)
+	}
+}
@@ -1,13 +1,188 @@
 package content
 
+import "strings"
+
 type content struct {
-	UUID           string   `json:"uuid,omitempty"`
-	Title          string   `json:"title,omitempty"`
-	PublishedDate  string   `json:"publishedDate,omitempty"`
-	Body           string   `json:"body,omitempty"`
-	Type           string   `json:"type,omitempty"`
-	StoryPackage   string   `json:"storyPackage,omitempty"`
-	ContentPackage string   `json:"contentPackage,omitempty"`
-	EditorialDesk  string   `json:"editorialDesk,omitempty"`
-	Publication    []string `json:"publication,omitempty"`
+	UUID          string `json:"uuid,omitempty"`
+	Title         string `json:"title,omitempty"`
+	PublishedDate string `json:"publishedDate,omitempty"`
+	// FirstPublishedDate is optional, when it is missing Write keeps the earliest date ever stored
+	FirstPublishedDate string `json:"firstPublishedDate,omitempty"`
+	Body               string `json:"body,omitempty"`
+	Type               string `json:"type,omitempty"`
+	// StoryPackage and ContentPackage are kept for payloads linking to a single package,
+	// they are merged with StoryPackages and ContentPackages when writing.
+	StoryPackage    string   `json:"storyPackage,omitempty"`
+	ContentPackage  string   `json:"contentPackage,omitempty"`
+	StoryPackages   []string `json:"storyPackages,omitempty"`
+	ContentPackages []string `json:"contentPackages,omitempty"`
+	LiveBlogPackage string   `json:"liveBlogPackage,omitempty"`
+	EditorialDesk   string   `json:"editorialDesk,omitempty"`
+	Publication     []string `json:"publication,omitempty"`
+	// Labels are only returned when reading, they are derived from the other fields when writing
+	Labels []string `json:"labels,omitempty"`
+	// Embeds is only returned by ReadWithOptions when requested, as it is derived from the body
+	Embeds []string `json:"embeds,omitempty"`
+	// bodyMetrics are only returned when reading, they are derived from the body when writing
+	bodyMetrics
+}
+
+// member is a content item linked to a package, see Service.Members
+type member struct {
+	UUID          string `json:"uuid"`
+	Type          string `json:"type,omitempty"`
+	Title         string `json:"title,omitempty"`
+	PublishedDate string `json:"publishedDate,omitempty"`
+}
+
+// membersPage is a page of the members of a package, Total being the number of members across all pages
+type membersPage struct {
+	UUID    string   `json:"uuid"`
+	Total   int      `json:"total"`
+	Offset  int      `json:"offset"`
+	Limit   int      `json:"limit"`
+	Members []member `json:"members"`
+}
+
+// batchReadResult is the content found by ReadBatch along with the UUIDs of the missing content
+type batchReadResult struct {
+	Content []content `json:"content"`
+	Missing []string  `json:"missing"`
+}
+
+// contentPage is a page of the content listed by List, NextCursor being empty on the last page
+type contentPage struct {
+	Content    []content `json:"content"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
+// searchPage is a page of the content matching a search, Total being the number of matches across all pages
+// up to maxSearchTotal, in which case TotalCapped tells that there are more matches
+type searchPage struct {
+	Query       string         `json:"query"`
+	Total       int            `json:"total"`
+	TotalCapped bool           `json:"totalCapped,omitempty"`
+	Offset      int            `json:"offset"`
+	Limit       int            `json:"limit"`
+	Results     []searchResult `json:"results"`
+}
+
+// searchResult is a content matching a search, the higher the Score the better the match
+type searchResult struct {
+	UUID          string  `json:"uuid"`
+	Title         string  `json:"title,omitempty"`
+	Type          string  `json:"type,omitempty"`
+	PublishedDate string  `json:"publishedDate,omitempty"`
+	Score         float64 `json:"score"`
+}
+
+// schemaStatus is the status of the schema migrations, LockedBy being the owner of the lock while migrating
+type schemaStatus struct {
+	CurrentVersion int                     `json:"currentVersion"`
+	LatestVersion  int                     `json:"latestVersion"`
+	LockedBy       string                  `json:"lockedBy,omitempty"`
+	Migrations     []schemaMigrationStatus `json:"migrations"`
+}
+
+type schemaMigrationStatus struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+	AppliedAt   string `json:"appliedAt,omitempty"`
+}
+
+// placeholderReport is the outcome of CollectPlaceholders, Count being the number of orphaned placeholders
+// deleted or, for a dry run, which would be deleted, a sample of them being listed in UUIDs
+type placeholderReport struct {
+	DryRun bool     `json:"dryRun"`
+	Count  int      `json:"count"`
+	UUIDs  []string `json:"uuids"`
+}
+
+// countBreakdown is the number of content items broken down by CountBy, Total counting every content once
+type countBreakdown struct {
+	GroupBy string       `json:"groupBy"`
+	Total   int          `json:"total"`
+	Groups  []countGroup `json:"groups"`
+}
+
+// countGroup is the number of content items sharing the same key, which is null for the content without any
+type countGroup struct {
+	Key   *string `json:"key"`
+	Count int     `json:"count"`
+}
+
+// the statuses of a deleteOutcome
+const (
+	deleteDeleted  = "deleted"
+	deleteNotFound = "notFound"
+	deleteFailed   = "failed"
+)
+
+// deleteOutcome is the outcome of the deletion of a content item by DeleteBatch
+type deleteOutcome struct {
+	UUID   string `json:"uuid"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// allStoryPackages returns the distinct story packages of the content, whichever field they were sent in
+func (c content) allStoryPackages() []string {
+	return mergeUUIDs(c.StoryPackage, c.StoryPackages)
+}
+
+// allContentPackages returns the distinct content packages of the content, whichever field they were sent in
+func (c content) allContentPackages() []string {
+	return mergeUUIDs(c.ContentPackage, c.ContentPackages)
+}
+
+// editorialDesks returns the normalised editorial desk of the content as a list, which is empty when there is no desk
+func (c content) editorialDesks() []string {
+	desk := normaliseEditorialDesk(c.EditorialDesk)
+	if desk == "" {
+		return []string{}
+	}
+	return []string{desk}
+}
+
+// normaliseEditorialDesk trims every segment of the desk path and drops the empty ones,
+// so that "FT/ Standard Content/" and "/FT/Standard Content" identify the same desk.
+func normaliseEditorialDesk(desk string) string {
+	var segments []string
+	for _, segment := range strings.Split(desk, "/") {
+		segment = strings.TrimSpace(segment)
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// positionEntries returns the entries of a managed relationship with a position property, see managedRelationship,
+// for the given UUIDs, their position being their index so that they are read in the same order
+func positionEntries(uuids []string) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(uuids))
+	for i, uuid := range uuids {
+		entries = append(entries, map[string]interface{}{
+			"uuid":     uuid,
+			"position": i,
+		})
+	}
+	return entries
+}
+
+func mergeUUIDs(single string, list []string) []string {
+	uuids := []string{}
+	seen := map[string]bool{}
+	for _, uuid := range append([]string{single}, list...) {
+		if uuid == "" || seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		uuids = append(uuids, uuid)
+	}
+	return uuids
 }
@@ -0,0 +1,61 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseEditorialDesk(t *testing.T) {
+	tests := map[string]struct {
+		desk     string
+		expected string
+	}{
+		"already normalised": {
+			desk:     "/FT/Standard Content",
+			expected: "/FT/Standard Content",
+		},
+		"missing leading slash": {
+			desk:     "FT/Standard Content",
+			expected: "/FT/Standard Content",
+		},
+		"trailing slash and spaces": {
+			desk:     " /FT/ Standard Content /",
+			expected: "/FT/Standard Content",
+		},
+		"repeated slashes": {
+			desk:     "/FT//Standard Content",
+			expected: "/FT/Standard Content",
+		},
+		"empty": {
+			desk:     "",
+			expected: "",
+		},
+		"only slashes": {
+			desk:     " / / ",
+			expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, normaliseEditorialDesk(test.desk))
+		})
+	}
+}
+
+func TestAllPackages(t *testing.T) {
+	c := content{
+		StoryPackage:    "3b08c76c-7479-461d-9f0e-a4e92dca56f7",
+		StoryPackages:   []string{"9a3f4d6e-5c8b-4a7f-8e1d-2b6c0f4e7a19", "3b08c76c-7479-461d-9f0e-a4e92dca56f7"},
+		ContentPackages: []string{"45163790-eec9-11e6-abbc-ee7d9c5b3b90", ""},
+	}
+
+	assert.Equal(
+		t,
+		[]string{"3b08c76c-7479-461d-9f0e-a4e92dca56f7", "9a3f4d6e-5c8b-4a7f-8e1d-2b6c0f4e7a19"},
+		c.allStoryPackages(),
+	)
+	assert.Equal(t, []string{"45163790-eec9-11e6-abbc-ee7d9c5b3b90"}, c.allContentPackages())
+	assert.Equal(t, []string{}, content{}.allStoryPackages())
+}
@@ -0,0 +1,149 @@
+package content
+
+import (
+	"errors"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// AddStoryPackage - relates existing content to the story package curating it, without rewriting the content.
+// It returns false when there is no such content.
+func (cd Service) AddStoryPackage(uuid string, packageUUID string, transID string) (bool, error) {
+	if err := validatePackage(uuid, "storyPackage", packageUUID); err != nil {
+		return false, err
+	}
+	return cd.writePackageRelation(addStoryPackageRelationQuery(uuid, packageUUID))
+}
+
+// RemoveStoryPackage - removes the relationship between content and a story package curating it.
+// It returns false when there is no such content or relationship.
+func (cd Service) RemoveStoryPackage(uuid string, packageUUID string, transID string) (bool, error) {
+	if err := validatePackage(uuid, "storyPackage", packageUUID); err != nil {
+		return false, err
+	}
+	return cd.writePackageRelation(removeStoryPackageRelationQuery(uuid, packageUUID))
+}
+
+// AddContentPackage - relates existing content to a content package it contains, without rewriting the content.
+// The content gets the ContentPackage label, as when it is written with contentPackages.
+// It returns false when there is no such content.
+func (cd Service) AddContentPackage(uuid string, packageUUID string, transID string) (bool, error) {
+	if err := validatePackage(uuid, "contentPackage", packageUUID); err != nil {
+		return false, err
+	}
+	return cd.writePackageRelation(addContentPackageRelationQuery(uuid, packageUUID))
+}
+
+// RemoveContentPackage - removes the relationship between content and a content package it contains.
+// The content loses the ContentPackage label along with its last content package.
+// It returns false when there is no such content or relationship.
+func (cd Service) RemoveContentPackage(uuid string, packageUUID string, transID string) (bool, error) {
+	if err := validatePackage(uuid, "contentPackage", packageUUID); err != nil {
+		return false, err
+	}
+	return cd.writePackageRelation(removeContentPackageRelationQuery(uuid, packageUUID))
+}
+
+func validatePackage(uuid string, field string, packageUUID string) error {
+	if errs := (content{UUID: uuid}).validateRelatedUUID(field, packageUUID); len(errs) > 0 {
+		return newValidationError(errs...)
+	}
+	return nil
+}
+
+// writePackageRelation runs a package relation query, which returns a row only when the content exists
+// telling whether the relationship was changed
+func (cd Service) writePackageRelation(query *cmneo4j.Query) (bool, error) {
+	var results []struct {
+		Changed bool `json:"changed"`
+	}
+	query.Result = &results
+
+	err := cd.driver.Write(query)
+	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return results[0].Changed, nil
+}
+
+// incrementVersionCypher increments the version of the content c changed by a package relation query,
+// so that a concurrent patch does not overwrite the change, see writeContentQuery.
+// Setting the uuid write locks the content before its version is read.
+const incrementVersionCypher = `
+			SET c.uuid = c.uuid
+			WITH *
+			SET c.version = coalesce(c.version, 0) + 1`
+
+// addStoryPackageRelationQuery merges the story package and its IS_CURATED_FOR relationship to the content,
+// an already existing relationship being left untouched
+func addStoryPackageRelationQuery(contentUUID string, packageUUID string) *cmneo4j.Query {
+	return &cmneo4j.Query{
+		Cypher: `MATCH (c:Content {uuid: $contentUuid})
+			MERGE (sp:Thing {uuid: $packageUuid})
+			ON CREATE SET sp.placeholderOf = $placeholderOf
+			MERGE (c)<-[rel:IS_CURATED_FOR]-(sp)` + incrementVersionCypher + `
+			RETURN true AS changed`,
+		Params: map[string]interface{}{
+			"packageUuid":   packageUUID,
+			"contentUuid":   contentUUID,
+			"placeholderOf": placeholderTag,
+		},
+	}
+}
+
+func removeStoryPackageRelationQuery(contentUUID string, packageUUID string) *cmneo4j.Query {
+	return &cmneo4j.Query{
+		Cypher: `MATCH (c:Content {uuid: $contentUuid})
+			OPTIONAL MATCH (c)<-[rel:IS_CURATED_FOR]-(:Thing {uuid: $packageUuid})
+			WITH c, collect(rel) AS rels
+			FOREACH (rel IN rels | DELETE rel)` + incrementVersionCypher + `
+			RETURN size(rels) > 0 AS changed`,
+		Params: map[string]interface{}{
+			"packageUuid": packageUUID,
+			"contentUuid": contentUUID,
+		},
+	}
+}
+
+// addContentPackageRelationQuery merges the content package and the CONTAINS relationship to it,
+// setting the labels getContentLabels derives from contentPackages
+func addContentPackageRelationQuery(contentUUID string, packageUUID string) *cmneo4j.Query {
+	return &cmneo4j.Query{
+		Cypher: `MATCH (c:Content {uuid: $contentUuid})
+			MERGE (cp:Thing {uuid: $packageUuid})
+			ON CREATE SET cp.placeholderOf = $placeholderOf
+			MERGE (c)-[rel:CONTAINS]->(cp)
+			SET c:ContentPackage
+			FOREACH (_ IN CASE WHEN c.type = $liveBlogPackage THEN [1] ELSE [] END | SET c:LiveBlogPackage)` + incrementVersionCypher + `
+			RETURN true AS changed`,
+		Params: map[string]interface{}{
+			"packageUuid":     packageUUID,
+			"contentUuid":     contentUUID,
+			"liveBlogPackage": LiveBlogPackage,
+			"placeholderOf":   placeholderTag,
+		},
+	}
+}
+
+// removeContentPackageRelationQuery deletes the CONTAINS relationship to the content package and, when it was
+// the last one, removes the labels getContentLabels only derives from contentPackages
+func removeContentPackageRelationQuery(contentUUID string, packageUUID string) *cmneo4j.Query {
+	return &cmneo4j.Query{
+		Cypher: `MATCH (c:Content {uuid: $contentUuid})
+			OPTIONAL MATCH (c)-[rel:CONTAINS]->(:Thing {uuid: $packageUuid})
+			WITH c, collect(rel) AS rels
+			FOREACH (rel IN rels | DELETE rel)
+			WITH c, rels
+			OPTIONAL MATCH (c)-[remaining:CONTAINS]->(:Thing)
+			WITH c, rels, count(remaining) AS remainingCount
+			FOREACH (_ IN CASE WHEN remainingCount = 0 THEN [1] ELSE [] END | REMOVE c:ContentPackage:LiveBlogPackage)` + incrementVersionCypher + `
+			RETURN size(rels) > 0 AS changed`,
+		Params: map[string]interface{}{
+			"packageUuid": packageUUID,
+			"contentUuid": contentUUID,
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// ErrPatchNotWritten is returned by Patch when the patched content is not eligible for being written,
+// in which case a PUT of it would be skipped
+var ErrPatchNotWritten = errors.New("the patched content would not be written: it is special content, " +
+	"or it has no body and its type is not one of the types without a body")
+
+// ErrPatchConflict is returned by Patch when the content was written by another request between the read
+// of the content the patch applies to and the write of the patched content
+var ErrPatchConflict = errors.New("the content was modified while it was being patched")
+
+// Patch - applies a JSON merge patch (RFC 7396) to the stored content and writes the result following
+// the same rules as Write. The patch applies to the writable fields of the content as returned by Read,
+// where packages are listed in storyPackages and contentPackages. The body is not stored, so when the patch
+// does not hold one the data derived from the stored body is kept. It returns false when there is no such content,
+// ErrPatchNotWritten when the patched content is not eligible for being written, and ErrPatchConflict when
+// the content was written since it was read, in which case the patch is not applied.
+func (cd Service) Patch(uuid string, patch map[string]interface{}, transID string) (bool, error) {
+	result, found, err := cd.readContent(uuid, ReadOptions{})
+	if err != nil || !found {
+		return false, err
+	}
+	stored := result.toContent()
+
+	document, err := stored.patchableDocument()
+	if err != nil {
+		return true, err
+	}
+
+	patched, err := applyPatch(document, patch)
+	if err != nil {
+		return true, err
+	}
+
+	if patched.UUID != stored.UUID {
+		return true, newValidationError(FieldError{
+			Field:   "uuid",
+			Message: fmt.Sprintf("uuid %q of the patch does not match uuid %q of the content", patched.UUID, stored.UUID),
+		})
+	}
+
+	item, ok, err := cd.writeItem(patched, &stored)
+	if err != nil {
+		return true, err
+	}
+	if !ok {
+		return true, ErrPatchNotWritten
+	}
+
+	return true, cd.writeVersion(getContentLabels(patched), item, result.Version)
+}
+
+// writeVersion writes the item only when the stored content is still at the given version,
+// returning ErrPatchConflict otherwise
+func (cd Service) writeVersion(labels string, item map[string]interface{}, version int64) error {
+	item["expectedVersion"] = version
+
+	var results []struct {
+		UUID string `json:"uuid"`
+	}
+	query := writeContentQuery(labels, []interface{}{item})
+	query.Cypher += `
+		RETURN n.uuid AS uuid`
+	query.Result = &results
+
+	err := cd.driver.Write(query)
+	if errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return ErrPatchConflict
+	}
+	return err
+}
+
+// patchableDocument returns the writable fields of the stored content as the JSON document patches apply to
+func (c content) patchableDocument() (map[string]interface{}, error) {
+	writable := content{
+		UUID:               c.UUID,
+		Title:              c.Title,
+		PublishedDate:      c.PublishedDate,
+		FirstPublishedDate: c.FirstPublishedDate,
+		Type:               c.Type,
+		StoryPackages:      c.StoryPackages,
+		ContentPackages:    c.ContentPackages,
+		LiveBlogPackage:    c.LiveBlogPackage,
+		EditorialDesk:      c.EditorialDesk,
+		Publication:        c.Publication,
+	}
+
+	b, err := json.Marshal(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	err = json.Unmarshal(b, &document)
+	return document, err
+}
+
+// applyPatch merges the patch into the document and decodes the result as content.
+// A patched field holding a value of the wrong type is reported as a *ValidationError.
+func applyPatch(document map[string]interface{}, patch map[string]interface{}) (content, error) {
+	b, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return content{}, err
+	}
+
+	var patched content
+	err = json.Unmarshal(b, &patched)
+	if fieldErr, ok := typeFieldError(err); ok {
+		return content{}, newValidationError(fieldErr)
+	}
+	return patched, err
+}
+
+// mergePatch applies a JSON merge patch to the target as described by RFC 7396: the members of a patch
+// object are merged recursively into the target, null members are removed and any other patch value
+// replaces the target.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	result := make(map[string]interface{}, len(targetObject))
+	for name, value := range targetObject {
+		result[name] = value
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(result, name)
+			continue
+		}
+		result[name] = mergePatch(result[name], value)
+	}
+	return result
+}
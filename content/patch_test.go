@@ -0,0 +1,92 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergePatch runs the examples of RFC 7396 appendix A
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target+" + "+test.patch, func(t *testing.T) {
+			var target, patch interface{}
+			assert.NoError(t, json.Unmarshal([]byte(test.target), &target))
+			assert.NoError(t, json.Unmarshal([]byte(test.patch), &patch))
+
+			actual, err := json.Marshal(mergePatch(target, patch))
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(actual))
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	stored := content{
+		UUID:            "ce3f2f5e-33d1-4c36-89e3-51aa00fd5660",
+		Title:           "Wrong title",
+		PublishedDate:   "2014-07-08T13:52:52.000Z",
+		Type:            "Article",
+		StoryPackage:    "3b08c76c-7479-461d-9f0e-a4e92dca56f7",
+		StoryPackages:   []string{"3b08c76c-7479-461d-9f0e-a4e92dca56f7"},
+		EditorialDesk:   "/FT/Newsdesk",
+		Labels:          []string{"Article", "Content", "Thing"},
+		bodyMetrics:     bodyMetrics{WordCount: 10, ParagraphCount: 1, ReadingTimeMinutes: 1},
+		ContentPackages: []string{"45163790-eec9-11e6-abbc-ee7d9c5b3b90"},
+	}
+
+	document, err := stored.patchableDocument()
+	assert.NoError(t, err)
+
+	patched, err := applyPatch(document, map[string]interface{}{
+		"title":           "Right title",
+		"editorialDesk":   nil,
+		"contentPackages": []interface{}{},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, content{
+		UUID:            stored.UUID,
+		Title:           "Right title",
+		PublishedDate:   stored.PublishedDate,
+		Type:            stored.Type,
+		StoryPackages:   stored.StoryPackages,
+		ContentPackages: []string{},
+	}, patched)
+}
+
+func TestApplyPatchWithInvalidType(t *testing.T) {
+	_, err := applyPatch(map[string]interface{}{"uuid": "ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"}, map[string]interface{}{
+		"title": 42.0,
+	})
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []FieldError{{Field: "title", Message: "title cannot be a JSON number"}}, validationErr.Errors)
+	}
+}
@@ -0,0 +1,80 @@
+package content
+
+import (
+	"errors"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// placeholderSampleSize is the maximum number of UUIDs listed by a dry run of CollectPlaceholders
+const placeholderSampleSize = 100
+
+// placeholderTag is the placeholderOf property of the placeholders merged by this service, telling them apart from
+// the nodes merged by other writers. It is dropped when the placeholder is written as content.
+const placeholderTag = "content-rw-neo4j"
+
+// orphanedPlaceholderCypher matches the orphaned placeholders t: the nodes this service merges for the other end
+// of a relationship, such as a package which has not been published, hold nothing but the Thing label, a uuid
+// and the placeholder tag. They are orphaned once they have no relationships left, e.g. when the content was
+// deleted or republished without them, nothing referring to them anymore.
+const orphanedPlaceholderCypher = `MATCH (t:Thing)
+		WHERE t.placeholderOf = '` + placeholderTag + `' AND size(labels(t)) = 1 AND size(keys(t)) = 2 AND NOT (t)--()`
+
+// ErrPlaceholderCollectionLocked is returned by CollectPlaceholders when another instance is collecting them
+var ErrPlaceholderCollectionLocked = errors.New("the orphaned placeholders are being collected by another instance")
+
+// CollectPlaceholders - deletes the orphaned placeholder nodes in batches of batchSize, returning how many were
+// deleted. Only one instance deletes them at a time, the others returning ErrPlaceholderCollectionLocked.
+// A dry run deletes nothing but reports how many would be, along with a sample of their UUIDs.
+func (cd Service) CollectPlaceholders(dryRun bool) (placeholderReport, error) {
+	if dryRun {
+		return cd.orphanedPlaceholders()
+	}
+
+	owner := lockOwner()
+	locked, err := placeholderCollectionLock.take(cd.driver, owner)
+	if err != nil {
+		return placeholderReport{UUIDs: []string{}}, err
+	}
+	if !locked {
+		return placeholderReport{UUIDs: []string{}}, ErrPlaceholderCollectionLocked
+	}
+	defer cd.releaseLock(placeholderCollectionLock, owner)
+
+	deleted, err := cd.runInBatches(orphanedPlaceholderCypher+`
+		WITH t LIMIT $batchSize
+		DELETE t
+		RETURN count(t) as c`, "Deleted %d orphaned placeholders so far")
+	return placeholderReport{Count: deleted, UUIDs: []string{}}, err
+}
+
+func (cd Service) orphanedPlaceholders() (placeholderReport, error) {
+	var results []struct {
+		Count int      `json:"c"`
+		UUIDs []string `json:"uuids"`
+	}
+
+	query := &cmneo4j.Query{
+		Cypher: orphanedPlaceholderCypher + `
+		WITH t ORDER BY t.uuid
+		RETURN count(t) as c, collect(t.uuid)[..$sampleSize] as uuids`,
+		Params: map[string]interface{}{
+			"sampleSize": placeholderSampleSize,
+		},
+		Result: &results,
+	}
+
+	err := cd.driver.Read(query)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return placeholderReport{}, err
+	}
+
+	report := placeholderReport{DryRun: true, UUIDs: []string{}}
+	if len(results) > 0 {
+		report.Count = results[0].Count
+		if results[0].UUIDs != nil {
+			report.UUIDs = results[0].UUIDs
+		}
+	}
+	return report, nil
+}
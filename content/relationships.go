@@ -0,0 +1,109 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+// managedRelationship is a relationship between a content node and other nodes which is fully
+// maintained by Write: it is created when the other node is listed in the payload and removed otherwise.
+// When the param of the item is null rather than a list, the existing relationships are kept as they are.
+type managedRelationship struct {
+	// relType is the type of the relationship
+	relType string
+	// outgoing is true when the relationship goes from the content to the other node
+	outgoing bool
+	// label and key identify the other node, which is merged if it does not exist yet
+	label string
+	key   string
+	// extraLabels are set on the other node when the relationship is merged, e.g. ":Publication"
+	extraLabels string
+	// param is the entry of the write item listing the keys of the nodes the content should be related to
+	param string
+	// properties are set on the relationship from the entries of param, which are then maps holding
+	// the key of the other node along with these properties rather than plain keys
+	properties []string
+}
+
+var managedRelationships = []managedRelationship{
+	{relType: "IS_CURATED_FOR", outgoing: false, label: "Thing", key: "uuid", param: "storyPackages"},
+	{relType: "CONTAINS", outgoing: true, label: "Thing", key: "uuid", param: "contentPackages"},
+	{relType: "PUBLISHED_BY_DESK", outgoing: true, label: "EditorialDesk", key: "path", param: "editorialDesks"},
+	{relType: "PUBLISHED_IN", outgoing: true, label: "Thing", key: "uuid", param: "publications", extraLabels: ":Publication", properties: []string{"position"}},
+	{relType: "IS_POST_OF", outgoing: true, label: "Thing", key: "uuid", param: "liveBlogPackages"},
+	{relType: "LINKS_TO", outgoing: true, label: "Thing", key: "uuid", param: "links"},
+	{relType: "EMBEDS", outgoing: true, label: "Thing", key: "uuid", param: "embeds", properties: []string{"position"}},
+}
+
+func (r managedRelationship) pattern(rel string, other string) string {
+	if r.outgoing {
+		return fmt.Sprintf("(n)-[%s:%s]->(%s)", rel, r.relType, other)
+	}
+	return fmt.Sprintf("(n)<-[%s:%s]-(%s)", rel, r.relType, other)
+}
+
+// keysCypher is the list of the keys of the nodes listed in the item
+func (r managedRelationship) keysCypher() string {
+	if len(r.properties) == 0 {
+		return "item." + r.param
+	}
+	return fmt.Sprintf("[entry IN item.%s | entry.%s]", r.param, r.key)
+}
+
+// removeStaleCypher deletes the relationships to the nodes which are not listed in the item anymore.
+// Relationships which are still wanted are left untouched, so they keep their identity and properties.
+func (r managedRelationship) removeStaleCypher() string {
+	return fmt.Sprintf(`
+		WITH n, item
+		OPTIONAL MATCH %s
+		WHERE item.%s IS NOT NULL AND NOT other.%s IN %s
+		WITH n, item, collect(rel) AS staleRels
+		FOREACH (rel IN staleRels | DELETE rel)`,
+		r.pattern("rel", "other:"+r.label), r.param, r.key, r.keysCypher())
+}
+
+// mergeCypher creates the relationships to the nodes listed in the item which do not exist yet
+// and updates their properties.
+func (r managedRelationship) mergeCypher() string {
+	setOther := ""
+	if r.extraLabels != "" {
+		setOther = fmt.Sprintf(`
+			SET other%s`, r.extraLabels)
+	}
+	// only the plain things, such as packages and linked content, are merged as collectable placeholders
+	if r.label == "Thing" && r.extraLabels == "" {
+		setOther = fmt.Sprintf(`
+			ON CREATE SET other.placeholderOf = '%s'`, placeholderTag)
+	}
+
+	if len(r.properties) == 0 {
+		return fmt.Sprintf(`
+		FOREACH (otherKey IN coalesce(item.%s, []) |
+			MERGE (other:%s {%s: otherKey})%s
+			MERGE %s)`,
+			r.param, r.label, r.key, setOther, r.pattern("", "other"))
+	}
+
+	var setProperties strings.Builder
+	for _, p := range r.properties {
+		setProperties.WriteString(fmt.Sprintf(`
+			SET rel.%s = entry.%s`, p, p))
+	}
+	return fmt.Sprintf(`
+		FOREACH (entry IN coalesce(item.%s, []) |
+			MERGE (other:%s {%s: entry.%s})%s
+			MERGE %s%s)`,
+		r.param, r.label, r.key, r.key, setOther, r.pattern("rel", "other"), setProperties.String())
+}
+
+// relationshipsCypher reconciles all the managed relationships of the content node n with the item.
+func relationshipsCypher() string {
+	var sb strings.Builder
+	for _, r := range managedRelationships {
+		sb.WriteString(r.removeStaleCypher())
+	}
+	for _, r := range managedRelationships {
+		sb.WriteString(r.mergeCypher())
+	}
+	return sb.String()
+}
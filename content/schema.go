@@ -0,0 +1,193 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// schemaMigration is a versioned change of the schema of the content graph, such as an index.
+// Its statements must be idempotent, e.g. CREATE INDEX ... IF NOT EXISTS, as a migration interrupted
+// before being recorded is applied again.
+type schemaMigration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// schemaMigrations are applied in order by MigrateSchema, each of them once. New migrations are appended
+// with the next version, applied ones must never be changed.
+var schemaMigrations = []schemaMigration{
+	{
+		Version:     1,
+		Description: "Index the publishedDateTime of content",
+		Statements:  []string{`CREATE INDEX content_publishedDateTime IF NOT EXISTS FOR (n:Content) ON (n.publishedDateTime)`},
+	},
+	{
+		Version:     2,
+		Description: "Index the firstPublishedDateEpoch of content",
+		Statements:  []string{`CREATE INDEX content_firstPublishedDateEpoch IF NOT EXISTS FOR (n:Content) ON (n.firstPublishedDateEpoch)`},
+	},
+	{
+		Version:     3,
+		Description: "Full-text index the title and prefLabel of content",
+		Statements: []string{fmt.Sprintf(
+			`CREATE FULLTEXT INDEX %s IF NOT EXISTS FOR (n:Content) ON EACH [n.title, n.prefLabel]`, titleSearchIndex,
+		)},
+	},
+	{
+		Version:     4,
+		Description: "Index the publishedDateEpoch of content",
+		Statements:  []string{`CREATE INDEX content_publishedDateEpoch IF NOT EXISTS FOR (n:Content) ON (n.publishedDateEpoch)`},
+	},
+	{
+		Version:     5,
+		Description: "Index the uuid of things, such as the packages and publications merged when writing content",
+		Statements:  []string{`CREATE INDEX thing_uuid IF NOT EXISTS FOR (n:Thing) ON (n.uuid)`},
+	},
+	{
+		Version:     6,
+		Description: "Index the placeholderOf tag of things, matched when collecting the orphaned placeholders",
+		Statements:  []string{`CREATE INDEX thing_placeholderOf IF NOT EXISTS FOR (n:Thing) ON (n.placeholderOf)`},
+	},
+}
+
+const (
+	// schemaLockName identifies the SchemaMigrationLock node
+	schemaLockName = "schema"
+	// schemaLockRetryInterval is how often MigrateSchema tries to take the lock held by another owner
+	schemaLockRetryInterval = 5 * time.Second
+)
+
+// ErrSchemaMigrationLocked is returned by MigrateSchema when another instance kept migrating the schema
+// for longer than the wait
+var ErrSchemaMigrationLocked = errors.New("the schema is being migrated by another instance")
+
+// MigrateSchema applies the schema migrations which have not been applied yet, in order, and records each of
+// them as a SchemaMigration node. Only one instance migrates the schema at a time: when another one holds the
+// lock, it is retried until the wait elapses. It returns the number of applied migrations.
+func (cd Service) MigrateSchema(wait time.Duration) (int, error) {
+	owner := lockOwner()
+	deadline := time.Now().Add(wait)
+
+	for {
+		locked, err := schemaLock.take(cd.driver, owner)
+		if err != nil {
+			return 0, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().Add(schemaLockRetryInterval).After(deadline) {
+			return 0, ErrSchemaMigrationLocked
+		}
+		cd.log.Info("The schema is being migrated by another instance, waiting for it to complete")
+		time.Sleep(schemaLockRetryInterval)
+	}
+	defer cd.releaseLock(schemaLock, owner)
+
+	applied, err := cd.appliedSchemaVersions()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range schemaMigrations {
+		if _, found := applied[m.Version]; found {
+			continue
+		}
+
+		cd.log.Infof("Applying schema migration %d: %s", m.Version, m.Description)
+		// schema statements cannot be run along with data updates, so each of them runs in its own transaction
+		for _, statement := range m.Statements {
+			if err = cd.driver.Write(&cmneo4j.Query{Cypher: statement}); err != nil {
+				return count, fmt.Errorf("schema migration %d failed: %w", m.Version, err)
+			}
+		}
+
+		err = cd.driver.Write(&cmneo4j.Query{
+			Cypher: `MERGE (m:SchemaMigration {version: $version})
+				SET m.description = $description, m.appliedAt = datetime()`,
+			Params: map[string]interface{}{
+				"version":     m.Version,
+				"description": m.Description,
+			},
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// SchemaStatus - returns the schema migrations along with the time they were applied at, if they were,
+// and the owner of the lock when the schema is being migrated
+func (cd Service) SchemaStatus() (schemaStatus, error) {
+	applied, err := cd.appliedSchemaVersions()
+	if err != nil {
+		return schemaStatus{}, err
+	}
+
+	var locks []struct {
+		Owner string `json:"owner"`
+	}
+	err = cd.driver.Read(&cmneo4j.Query{
+		Cypher: `MATCH (l:SchemaMigrationLock {name: $name})
+			WHERE l.lockedUntil >= timestamp()
+			RETURN l.owner AS owner`,
+		Params: map[string]interface{}{
+			"name": schemaLockName,
+		},
+		Result: &locks,
+	})
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return schemaStatus{}, err
+	}
+
+	status := schemaStatus{
+		LatestVersion: schemaMigrations[len(schemaMigrations)-1].Version,
+		Migrations:    []schemaMigrationStatus{},
+	}
+	if len(locks) > 0 {
+		status.LockedBy = locks[0].Owner
+	}
+	for _, m := range schemaMigrations {
+		appliedAt, found := applied[m.Version]
+		if found && m.Version > status.CurrentVersion {
+			status.CurrentVersion = m.Version
+		}
+		status.Migrations = append(status.Migrations, schemaMigrationStatus{
+			Version:     m.Version,
+			Description: m.Description,
+			Applied:     found,
+			AppliedAt:   appliedAt,
+		})
+	}
+	return status, nil
+}
+
+// appliedSchemaVersions returns the time every applied migration was applied at, by version
+func (cd Service) appliedSchemaVersions() (map[int]string, error) {
+	var results []struct {
+		Version   int    `json:"version"`
+		AppliedAt string `json:"appliedAt"`
+	}
+
+	err := cd.driver.Read(&cmneo4j.Query{
+		Cypher: `MATCH (m:SchemaMigration)
+			RETURN m.version AS version, toString(m.appliedAt) AS appliedAt`,
+		Result: &results,
+	})
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return nil, err
+	}
+
+	applied := make(map[int]string, len(results))
+	for _, r := range results {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
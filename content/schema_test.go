@@ -0,0 +1,19 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaMigrationsAreOrderedAndIdempotent(t *testing.T) {
+	for i, m := range schemaMigrations {
+		assert.Equal(t, i+1, m.Version, "versions should follow each other from 1")
+		assert.NotEmpty(t, m.Description)
+		if assert.NotEmpty(t, m.Statements, "migration %d has no statements", m.Version) {
+			for _, statement := range m.Statements {
+				assert.Contains(t, statement, "IF NOT EXISTS", "statements of migration %d should be idempotent", m.Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package content
+
+import (
+	"errors"
+	"strings"
+
+	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
+)
+
+// maxSearchTotal is the maximum number of matches counted by Search
+const maxSearchTotal = 10000
+
+// Search - reads a page of the content whose title matches the words of the query, from the best match.
+// The query is searched as plain words, the full-text query syntax being escaped. When set, the type
+// filters the matches by label. The matches are counted up to maxSearchTotal.
+func (cd Service) Search(q string, contentType string, offset int, limit int) (searchPage, error) {
+	filter := contentFilter{Type: contentType}
+	if err := filter.validate(); err != nil {
+		return searchPage{}, err
+	}
+
+	// the matches are streamed, only the ones of the page being kept by the ordering
+	var results []searchResult
+	pageQuery := &cmneo4j.Query{
+		Cypher: `CALL db.index.fulltext.queryNodes($index, $query) YIELD node, score
+			WHERE $type = '' OR $type IN labels(node)
+			RETURN node.uuid AS uuid,
+				node.title AS title,
+				` + typeCypher("node") + ` AS type,
+				node.publishedDate AS publishedDate,
+				score
+			ORDER BY score DESC, uuid
+			SKIP $offset
+			LIMIT $limit`,
+		Params: map[string]interface{}{
+			"index":  titleSearchIndex,
+			"query":  escapeFullTextQuery(q),
+			"type":   contentType,
+			"offset": offset,
+			"limit":  limit,
+		},
+		Result: &results,
+	}
+
+	err := cd.driver.Read(pageQuery)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return searchPage{}, err
+	}
+
+	// the matches are counted up to one more than maxSearchTotal, which tells whether the total is capped
+	var counts []struct {
+		Total int `json:"total"`
+	}
+	countQuery := &cmneo4j.Query{
+		Cypher: `CALL db.index.fulltext.queryNodes($index, $query) YIELD node
+			WHERE $type = '' OR $type IN labels(node)
+			WITH node
+			LIMIT $maxTotal
+			RETURN count(node) AS total`,
+		Params: map[string]interface{}{
+			"index":    titleSearchIndex,
+			"query":    escapeFullTextQuery(q),
+			"type":     contentType,
+			"maxTotal": maxSearchTotal + 1,
+		},
+		Result: &counts,
+	}
+
+	err = cd.driver.Read(countQuery)
+	if err != nil && !errors.Is(err, cmneo4j.ErrNoResultsFound) {
+		return searchPage{}, err
+	}
+
+	page := searchPage{Query: q, Offset: offset, Limit: limit, Results: []searchResult{}}
+	if results != nil {
+		page.Results = results
+	}
+	if len(counts) > 0 {
+		page.Total = counts[0].Total
+	}
+	if page.Total > maxSearchTotal {
+		page.Total = maxSearchTotal
+		page.TotalCapped = true
+	}
+	return page, nil
+}
+
+// escapeFullTextQuery escapes the characters and operators of the Lucene query syntax,
+// so that the query is searched as plain words
+func escapeFullTextQuery(q string) string {
+	words := strings.Fields(q)
+	for i, word := range words {
+		// the operators are only recognised in upper case, while the index is case insensitive
+		if word == "AND" || word == "OR" || word == "NOT" {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+
+		var sb strings.Builder
+		for _, r := range word {
+			if strings.ContainsRune(`\+-!(){}[]^"~*?:/&|`, r) {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+		}
+		words[i] = sb.String()
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,22 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeFullTextQuery(t *testing.T) {
+	tests := map[string]string{
+		"Profits plunge at Vatican bank": "Profits plunge at Vatican bank",
+		"FT: Brexit - what now?":         `FT\: Brexit \- what now\?`,
+		`"quoted" title (2014)`:          `\"quoted\" title \(2014\)`,
+		"Bank AND   OR NOT NOTE":         "Bank and or not NOTE",
+		`a\b/c && d || e!`:               `a\\b\/c \&\& d \|\| e\!`,
+		"title~ score^2 [x TO y] {z} *":  `title\~ score\^2 \[x TO y\] \{z\} \*`,
+	}
+
+	for q, expected := range tests {
+		assert.Equal(t, expected, escapeFullTextQuery(q), q)
+	}
+}
@@ -0,0 +1,113 @@
+package content
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxTitleLength         = 1000
+	maxEditorialDeskLength = 256
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// allowedTypes are the content types which can be written, the type being added as a label to the content node
+var allowedTypes = map[string]bool{
+	"Content":             true,
+	"Article":             true,
+	"ContentPackage":      true,
+	"Video":               true,
+	"Graphic":             true,
+	"Audio":               true,
+	"Image":               true,
+	"ImageSet":            true,
+	"Clip":                true,
+	"ClipSet":             true,
+	"DynamicContent":      true,
+	"CustomCodeComponent": true,
+	LiveBlogPackage:       true,
+	LiveBlogPost:          true,
+	"LiveEvent":           true,
+}
+
+// validate checks the fields of the content, it returns a *ValidationError listing all the invalid fields
+func (c content) validate() error {
+	var errs []FieldError
+
+	if c.UUID == "" {
+		errs = append(errs, FieldError{Field: "uuid", Message: "uuid is required"})
+	} else if !uuidRegex.MatchString(c.UUID) {
+		errs = append(errs, FieldError{Field: "uuid", Message: fmt.Sprintf("%q is not a valid UUID", c.UUID)})
+	}
+
+	if utf8.RuneCountInString(c.Title) > maxTitleLength {
+		errs = append(errs, FieldError{Field: "title", Message: fmt.Sprintf("title must not be longer than %d characters", maxTitleLength)})
+	}
+
+	if c.Type != "" && !allowedTypes[c.Type] {
+		errs = append(errs, FieldError{Field: "type", Message: fmt.Sprintf("%q is not an allowed content type", c.Type)})
+	}
+
+	if utf8.RuneCountInString(c.EditorialDesk) > maxEditorialDeskLength {
+		errs = append(errs, FieldError{Field: "editorialDesk", Message: fmt.Sprintf("editorialDesk must not be longer than %d characters", maxEditorialDeskLength)})
+	}
+
+	errs = append(errs, validateDate("publishedDate", c.PublishedDate)...)
+	errs = append(errs, validateDate("firstPublishedDate", c.FirstPublishedDate)...)
+	errs = append(errs, c.validateRelatedUUID("storyPackage", c.StoryPackage)...)
+	errs = append(errs, c.validateRelatedUUIDs("storyPackages", c.StoryPackages)...)
+	errs = append(errs, c.validateRelatedUUID("contentPackage", c.ContentPackage)...)
+	errs = append(errs, c.validateRelatedUUIDs("contentPackages", c.ContentPackages)...)
+	if c.LiveBlogPackage != "" && c.Type != LiveBlogPost {
+		errs = append(errs, FieldError{Field: "liveBlogPackage", Message: fmt.Sprintf("liveBlogPackage is only allowed for %s content", LiveBlogPost)})
+	} else {
+		errs = append(errs, c.validateRelatedUUID("liveBlogPackage", c.LiveBlogPackage)...)
+	}
+	errs = append(errs, c.validateRelatedUUIDs("publication", c.Publication)...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return newValidationError(errs...)
+}
+
+func validateDate(field string, value string) []FieldError {
+	if value == "" {
+		return nil
+	}
+	if _, err := parsePublishedDate(value); err != nil {
+		return []FieldError{{Field: field, Message: err.Error()}}
+	}
+	return nil
+}
+
+// validateRelatedUUID checks that an optional field holding the UUID of another node is valid
+// and does not point to the content itself
+func (c content) validateRelatedUUID(field string, uuid string) []FieldError {
+	if uuid == "" {
+		return nil
+	}
+	if !uuidRegex.MatchString(uuid) {
+		return []FieldError{{Field: field, Message: fmt.Sprintf("%q is not a valid UUID", uuid)}}
+	}
+	if strings.EqualFold(uuid, c.UUID) {
+		return []FieldError{{Field: field, Message: "content cannot refer to itself"}}
+	}
+	return nil
+}
+
+func (c content) validateRelatedUUIDs(field string, uuids []string) []FieldError {
+	var errs []FieldError
+	for i, uuid := range uuids {
+		entry := fmt.Sprintf("%s[%d]", field, i)
+		if uuid == "" {
+			errs = append(errs, FieldError{Field: entry, Message: `"" is not a valid UUID`})
+			continue
+		}
+		errs = append(errs, c.validateRelatedUUID(entry, uuid)...)
+	}
+	return errs
+}
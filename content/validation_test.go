@@ -0,0 +1,118 @@
+package content
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	validationTestUUID      = "ce3f2f5e-33d1-4c36-89e3-51aa00fd5660"
+	validationTestOtherUUID = "3b08c76c-7479-461d-9f0e-a4e92dca56f7"
+)
+
+func TestValidateValidContent(t *testing.T) {
+	c := content{
+		UUID:               validationTestUUID,
+		Title:              "Profits plunge at Vatican bank",
+		PublishedDate:      "2014-07-08T13:52:52.000Z",
+		FirstPublishedDate: "2014-07-08T13:52:52",
+		Type:               "Article",
+		StoryPackage:       validationTestOtherUUID,
+		StoryPackages:      []string{validationTestOtherUUID},
+		ContentPackages:    []string{"45163790-EEC9-11E6-ABBC-EE7D9C5B3B90"},
+		EditorialDesk:      "/FT/Newsdesk",
+		Publication:        []string{"8e6c705e-1132-42a2-8db0-c295e29e8658"},
+	}
+
+	assert.NoError(t, c.validate())
+}
+
+func TestValidateInvalidContent(t *testing.T) {
+	tests := map[string]struct {
+		content        content
+		expectedFields []string
+	}{
+		"missing uuid": {
+			content:        content{},
+			expectedFields: []string{"uuid"},
+		},
+		"invalid uuid": {
+			content:        content{UUID: "not-a-uuid"},
+			expectedFields: []string{"uuid"},
+		},
+		"title too long": {
+			content:        content{UUID: validationTestUUID, Title: strings.Repeat("a", maxTitleLength+1)},
+			expectedFields: []string{"title"},
+		},
+		"type not allowed": {
+			content:        content{UUID: validationTestUUID, Type: "Thing:Concept"},
+			expectedFields: []string{"type"},
+		},
+		"editorial desk too long": {
+			content:        content{UUID: validationTestUUID, EditorialDesk: "/" + strings.Repeat("a", maxEditorialDeskLength)},
+			expectedFields: []string{"editorialDesk"},
+		},
+		"invalid dates": {
+			content:        content{UUID: validationTestUUID, PublishedDate: "yesterday", FirstPublishedDate: "08/07/2014"},
+			expectedFields: []string{"publishedDate", "firstPublishedDate"},
+		},
+		"invalid package uuids": {
+			content: content{
+				UUID:            validationTestUUID,
+				Type:            LiveBlogPost,
+				StoryPackage:    "sp",
+				StoryPackages:   []string{validationTestOtherUUID, ""},
+				ContentPackage:  "cp",
+				ContentPackages: []string{"cp"},
+				LiveBlogPackage: "lb",
+				Publication:     []string{"FTCOM"},
+			},
+			expectedFields: []string{
+				"storyPackage",
+				"storyPackages[1]",
+				"contentPackage",
+				"contentPackages[0]",
+				"liveBlogPackage",
+				"publication[0]",
+			},
+		},
+		"self references": {
+			content: content{
+				UUID:            validationTestUUID,
+				Type:            LiveBlogPost,
+				StoryPackages:   []string{validationTestOtherUUID, validationTestUUID},
+				ContentPackage:  strings.ToUpper(validationTestUUID),
+				LiveBlogPackage: validationTestUUID,
+			},
+			expectedFields: []string{"storyPackages[1]", "contentPackage", "liveBlogPackage"},
+		},
+		"live blog package of other content than a post": {
+			content:        content{UUID: validationTestUUID, Type: "Article", LiveBlogPackage: validationTestOtherUUID},
+			expectedFields: []string{"liveBlogPackage"},
+		},
+		"live blog package without type": {
+			content:        content{UUID: validationTestUUID, LiveBlogPackage: validationTestOtherUUID},
+			expectedFields: []string{"liveBlogPackage"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.content.validate()
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a validation error but got %v", err)
+			}
+
+			var fields []string
+			for _, fe := range validationErr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, test.expectedFields, fields)
+		})
+	}
+}
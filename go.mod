@@ -3,31 +3,28 @@ module github.com/Financial-Times/content-rw-neo4j/v3
 go 1.21
 
 require (
-	github.com/Financial-Times/base-ft-rw-app-go/v2 v2.0.0-20211207113529-d0faaa432d5d
+	github.com/Financial-Times/api-endpoint v1.0.0
 	github.com/Financial-Times/cm-neo4j-driver v1.1.0
 	github.com/Financial-Times/go-fthealth v0.0.0-20171204124831-1b007e2b37b7
 	github.com/Financial-Times/go-logger/v2 v2.0.1
+	github.com/Financial-Times/http-handlers-go v0.0.0-20170809121007-229ac16f1d9e
 	github.com/Financial-Times/opa-client-go v1.1.2
+	github.com/Financial-Times/service-status-go v0.0.0-20160323111542-3f5199736a3d
+	github.com/Financial-Times/transactionid-utils-go v1.0.0
 	github.com/google/go-cmp v0.5.9
+	github.com/gorilla/mux v1.8.1
 	github.com/jawher/mow.cli v0.0.0-20170430135212-8327d12beb75
+	github.com/rcrowley/go-metrics v0.0.0-20161128210544-1f30fe9094a5
 	github.com/stretchr/testify v1.7.0
 )
 
 require (
-	github.com/Financial-Times/api-endpoint v1.0.0 // indirect
-	github.com/Financial-Times/http-handlers-go v0.0.0-20170809121007-229ac16f1d9e // indirect
-	github.com/Financial-Times/service-status-go v0.0.0-20160323111542-3f5199736a3d // indirect
-	github.com/Financial-Times/transactionid-utils-go v1.0.0 // indirect
-	github.com/Financial-Times/up-rw-app-api-go v0.0.0-20170710125828-d9d93a1f6895 // indirect
-	github.com/cyberdelia/go-metrics-graphite v0.0.0-20161219230853-39f87cc3b432 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/dchest/uniuri v1.2.0 // indirect
-	github.com/gorilla/mux v1.8.1 // indirect
 	github.com/hashicorp/go-version v1.2.0 // indirect
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/neo4j/neo4j-go-driver/v4 v4.3.3 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/rcrowley/go-metrics v0.0.0-20161128210544-1f30fe9094a5 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
 	golang.org/x/net v0.22.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
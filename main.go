@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/Financial-Times/opa-client-go"
@@ -11,15 +14,22 @@ import (
 
 	cli "github.com/jawher/mow.cli"
 
-	"github.com/Financial-Times/base-ft-rw-app-go/v2/baseftrwapp"
+	api "github.com/Financial-Times/api-endpoint"
 	cmneo4j "github.com/Financial-Times/cm-neo4j-driver"
 	"github.com/Financial-Times/content-rw-neo4j/v3/content"
 	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
 	"github.com/Financial-Times/go-logger/v2"
+	"github.com/Financial-Times/http-handlers-go/httphandlers"
+	"github.com/Financial-Times/service-status-go/gtg"
+	status "github.com/Financial-Times/service-status-go/httphandlers"
+	"github.com/gorilla/mux"
+	metrics "github.com/rcrowley/go-metrics"
 )
 
 const (
 	appDescription = "A RESTful API for managing content (bare-bones representation as full content is served from MongoDB) in Neo4j"
+	// schemaMigrationWait is how long to wait for another instance migrating the schema
+	schemaMigrationWait = 15 * time.Minute
 )
 
 func main() {
@@ -63,10 +73,59 @@ func main() {
 	batchSize := app.Int(cli.IntOpt{
 		Name:   "batchSize",
 		Value:  1024,
-		Desc:   "Maximum number of statements to execute per batch",
+		Desc:   "Maximum number of content items written by a single statement in bulk mode",
 		EnvVar: "BATCH_SIZE",
 	})
 
+	rejectUnknownFields := app.Bool(cli.BoolOpt{
+		Name:   "rejectUnknownFields",
+		Value:  false,
+		Desc:   "Reject the written content holding fields which are not part of the model, instead of ignoring them",
+		EnvVar: "REJECT_UNKNOWN_FIELDS",
+	})
+
+	canonicaliseUUIDCase := app.Bool(cli.BoolOpt{
+		Name:   "canonicaliseUUIDCase",
+		Value:  false,
+		Desc:   "Lower case the content UUIDs of the requests, so that UUIDs which only differ by case identify the same content",
+		EnvVar: "CANONICALISE_UUID_CASE",
+	})
+
+	maxReadBatchSize := app.Int(cli.IntOpt{
+		Name:   "maxReadBatchSize",
+		Value:  content.DefaultMaxReadBatchSize,
+		Desc:   "Maximum number of UUIDs which can be read by a single batch read request",
+		EnvVar: "MAX_READ_BATCH_SIZE",
+	})
+
+	maxDeleteBatchSize := app.Int(cli.IntOpt{
+		Name:   "maxDeleteBatchSize",
+		Value:  content.DefaultMaxDeleteBatchSize,
+		Desc:   "Maximum number of UUIDs which can be deleted by a single bulk delete request",
+		EnvVar: "MAX_DELETE_BATCH_SIZE",
+	})
+
+	maxWriteBatchSize := app.Int(cli.IntOpt{
+		Name:   "maxWriteBatchSize",
+		Value:  content.DefaultMaxWriteBatchSize,
+		Desc:   "Maximum number of content items which can be written by a single bulk write request",
+		EnvVar: "MAX_WRITE_BATCH_SIZE",
+	})
+
+	migrateSchemaOnStartup := app.Bool(cli.BoolOpt{
+		Name:   "migrateSchemaOnStartup",
+		Value:  true,
+		Desc:   "Apply the pending schema migrations at startup, otherwise they are applied with the migrate-schema command",
+		EnvVar: "MIGRATE_SCHEMA_ON_STARTUP",
+	})
+
+	placeholderCollectionInterval := app.String(cli.StringOpt{
+		Name:   "placeholderCollectionInterval",
+		Value:  "",
+		Desc:   "Interval between the deletions of the orphaned placeholder nodes, e.g. 24h, never when not set",
+		EnvVar: "PLACEHOLDER_COLLECTION_INTERVAL",
+	})
+
 	dbDriverLogLevel := app.String(cli.StringOpt{
 		Name:   "dbDriverLogLevel",
 		Value:  "WARN",
@@ -95,20 +154,67 @@ func main() {
 		"batchSize":     *batchSize,
 	}).Info("Application starting...")
 
+	app.Command(
+		"migrate-schema",
+		"Applies the pending schema migrations, such as new indexes, and records them as SchemaMigration nodes",
+		func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				driver := newDriver(*neoURL, *appName, *dbDriverLogLevel, log)
+				defer closeDriver(driver, log)
+
+				// the policy agent is not needed as no content is written
+				contentService := content.NewContentService(driver, nil, *batchSize, log)
+				if err := contentService.Initialise(); err != nil {
+					log.WithError(err).Fatal("Content service could not be initialised")
+				}
+				migrateSchema(contentService, log)
+			}
+		},
+	)
+
+	app.Command(
+		"migrate-publications",
+		"Converts the publication array property of existing content nodes into relationships to Publication nodes",
+		func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				driver := newDriver(*neoURL, *appName, *dbDriverLogLevel, log)
+				defer closeDriver(driver, log)
+
+				// the policy agent is not needed as no content is written
+				contentService := content.NewContentService(driver, nil, *batchSize, log)
+				migrated, err := contentService.MigratePublications()
+				if err != nil {
+					log.WithError(err).Fatalf("Publications migration failed after migrating %d content nodes", migrated)
+				}
+				log.Infof("Publications migration completed, %d content nodes migrated", migrated)
+			}
+		},
+	)
+
+	app.Command(
+		"migrate-published-datetime",
+		"Sets the native datetime publishedDateTime property of existing content nodes from their publishedDate",
+		func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				driver := newDriver(*neoURL, *appName, *dbDriverLogLevel, log)
+				defer closeDriver(driver, log)
+
+				// the policy agent is not needed as no content is written
+				contentService := content.NewContentService(driver, nil, *batchSize, log)
+				migrated, err := contentService.MigratePublishedDateTime()
+				if err != nil {
+					log.WithError(err).Fatalf("Published datetime migration failed after migrating %d content nodes", migrated)
+				}
+				log.Infof("Published datetime migration completed, %d content nodes migrated", migrated)
+			}
+		},
+	)
+
 	app.Action = func() {
 		log.Infof("Application started with args %s", os.Args)
-		dbLog := logger.NewUPPLogger(*appName+"-cmneo4j-driver", *dbDriverLogLevel)
 
-		driver, err := cmneo4j.NewDefaultDriver(*neoURL, dbLog)
-		if err != nil {
-			log.WithError(err).Fatal("Could not create a new instance of cmneo4j driver")
-		}
-		defer func(driver *cmneo4j.Driver) {
-			err = driver.Close()
-			if err != nil {
-				log.WithError(err).Error("could not close the cmneo4j driver instance.")
-			}
-		}(driver)
+		driver := newDriver(*neoURL, *appName, *dbDriverLogLevel, log)
+		defer closeDriver(driver, log)
 
 		paths := map[string]string{
 			policy.SpecialContentKey: *opaSpecialContentPolicyPath,
@@ -116,20 +222,27 @@ func main() {
 		opaClient := opa.NewOpenPolicyAgentClient(*opaURL, paths, opa.WithLogger(log))
 		agent := policy.NewOpenPolicyAgent(opaClient, log)
 
-		contentDriver := content.NewContentService(driver, agent, log)
-
-		services := map[string]baseftrwapp.Service{
-			"content": contentDriver,
+		contentService := content.NewContentService(driver, agent, *batchSize, log)
+		err := contentService.Initialise()
+		if err != nil {
+			log.WithError(err).Fatal("Content service could not startup")
 		}
+		// the schema is migrated once the server is listening, so that the instance is not restarted by its
+		// liveness probe while waiting for another one to migrate it, and it is not good to go until then
+		schemaMigrated := &atomic.Bool{}
+		schemaMigrated.Store(!*migrateSchemaOnStartup)
 
-		var checks []fthealth.Check
-		for _, service := range services {
-			checks = append(checks, makeCheck(service, driver))
+		if *placeholderCollectionInterval != "" {
+			interval, err := time.ParseDuration(*placeholderCollectionInterval)
+			if err != nil || interval <= 0 {
+				log.WithError(err).Fatalf("Invalid placeholder collection interval %q", *placeholderCollectionInterval)
+			}
+			go collectPlaceholders(contentService, interval, log)
 		}
 
 		ymlBytes, err := os.ReadFile(*apiYml)
 		if err != nil {
-			ymlBytes = nil // the base-ft-rw-app-go lib will not add the /__api endpoint if OpenAPIData is nil
+			ymlBytes = nil // the /__api endpoint is not added if the OpenAPI file cannot be read
 		}
 
 		hc := fthealth.TimedHealthCheck{
@@ -137,20 +250,38 @@ func main() {
 				SystemCode:  "upp-content-rw-neo4j",
 				Name:        "ft-content_rw_neo4j ServiceModule",
 				Description: "Writes 'content' to Neo4j, usually as part of a bulk upload done on a schedule",
-				Checks:      checks,
+				Checks:      []fthealth.Check{makeCheck(contentService, driver)},
 			},
 			Timeout: 10 * time.Second,
 		}
 
-		baseftrwapp.RunServerWithConf(baseftrwapp.RWConf{
-			Services:      services,
-			HealthHandler: fthealth.Handler(hc),
-			Port:          *port,
-			ServiceName:   "content-rw-neo4j",
-			Env:           "local",
-			EnableReqLog:  true,
-			OpenAPIData:   ymlBytes,
-		})
+		router := mux.NewRouter()
+		handlerConfig := content.HandlerConfig{
+			RejectUnknownFields:  *rejectUnknownFields,
+			CanonicaliseUUIDCase: *canonicaliseUUIDCase,
+			MaxReadBatchSize:     *maxReadBatchSize,
+			MaxDeleteBatchSize:   *maxDeleteBatchSize,
+			MaxWriteBatchSize:    *maxWriteBatchSize,
+		}
+		content.NewHandler(contentService, handlerConfig, log).RegisterHandlers(router)
+		registerAdminHandlers(router, fthealth.Handler(hc), contentService, schemaMigrated, ymlBytes, log)
+
+		var h http.Handler = router
+		h = httphandlers.TransactionAwareRequestLoggingHandler(log.Logger, h)
+		h = httphandlers.HTTPMetricsHandler(metrics.DefaultRegistry, h)
+
+		if !schemaMigrated.Load() {
+			go func() {
+				migrateSchema(contentService, log)
+				schemaMigrated.Store(true)
+			}()
+		}
+
+		log.Infof("Listening on %d", *port)
+		err = http.ListenAndServe(fmt.Sprintf(":%d", *port), h)
+		if err != nil {
+			log.WithError(err).Error("HTTP server stopped")
+		}
 	}
 	err := app.Run(os.Args)
 	if err != nil {
@@ -158,7 +289,92 @@ func main() {
 	}
 }
 
-func makeCheck(service baseftrwapp.Service, cd *cmneo4j.Driver) fthealth.Check {
+// migrateSchema applies the pending schema migrations of the initialised content service
+func migrateSchema(contentService content.Service, log *logger.UPPLogger) {
+	applied, err := contentService.MigrateSchema(schemaMigrationWait)
+	if err != nil {
+		log.WithError(err).Fatalf("Schema migration failed after applying %d migrations", applied)
+	}
+	log.Infof("Schema migration completed, %d migrations applied", applied)
+}
+
+// collectPlaceholders deletes the orphaned placeholder nodes at every interval, unless another instance is
+// already deleting them
+func collectPlaceholders(contentService content.Service, interval time.Duration, log *logger.UPPLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report, err := contentService.CollectPlaceholders(false)
+		if errors.Is(err, content.ErrPlaceholderCollectionLocked) {
+			log.Info("Orphaned placeholders are being collected by another instance, skipping this collection")
+			continue
+		}
+		if err != nil {
+			log.WithError(err).Errorf("Orphaned placeholders collection failed after deleting %d placeholders", report.Count)
+			continue
+		}
+		log.Infof("Orphaned placeholders collection completed, %d placeholders deleted", report.Count)
+	}
+}
+
+func newDriver(neoURL string, appName string, dbDriverLogLevel string, log *logger.UPPLogger) *cmneo4j.Driver {
+	dbLog := logger.NewUPPLogger(appName+"-cmneo4j-driver", dbDriverLogLevel)
+
+	driver, err := cmneo4j.NewDefaultDriver(neoURL, dbLog)
+	if err != nil {
+		log.WithError(err).Fatal("Could not create a new instance of cmneo4j driver")
+	}
+	return driver
+}
+
+func closeDriver(driver *cmneo4j.Driver, log *logger.UPPLogger) {
+	err := driver.Close()
+	if err != nil {
+		log.WithError(err).Error("could not close the cmneo4j driver instance.")
+	}
+}
+
+// registerAdminHandlers registers the health, good-to-go, ping, build-info and API endpoints
+func registerAdminHandlers(
+	router *mux.Router,
+	healthHandler func(http.ResponseWriter, *http.Request),
+	service content.Service,
+	schemaMigrated *atomic.Bool,
+	apiYml []byte,
+	log *logger.UPPLogger,
+) {
+	if len(apiYml) != 0 {
+		endpoint, err := api.NewAPIEndpointForYAML(apiYml)
+		if err != nil {
+			log.WithError(err).Warn("Failed to serve API endpoint, please check whether the OpenAPI file is valid")
+		} else {
+			router.HandleFunc(api.DefaultPath, endpoint.ServeHTTP)
+		}
+	}
+
+	router.HandleFunc("/__health", healthHandler)
+	router.HandleFunc(status.PingPath, status.PingHandler)
+	router.HandleFunc(status.PingPathDW, status.PingHandler)
+	router.HandleFunc(status.BuildInfoPath, status.BuildInfoHandler)
+	router.HandleFunc(status.BuildInfoPathDW, status.BuildInfoHandler)
+
+	gtgChecker := func() gtg.Status {
+		if err := service.Check(); err != nil {
+			return gtg.Status{GoodToGo: false, Message: err.Error()}
+		}
+		return gtg.Status{GoodToGo: true}
+	}
+	schemaChecker := func() gtg.Status {
+		if !schemaMigrated.Load() {
+			return gtg.Status{GoodToGo: false, Message: "The schema is being migrated"}
+		}
+		return gtg.Status{GoodToGo: true}
+	}
+	router.HandleFunc(status.GTGPath, status.NewGoodToGoHandler(gtg.FailFastParallelCheck([]gtg.StatusChecker{gtgChecker, schemaChecker})))
+}
+
+func makeCheck(service content.Service, cd *cmneo4j.Driver) fthealth.Check {
 	return fthealth.Check{
 		BusinessImpact: "Cannot read/write content via this writer",
 		Name:           "Check connectivity to Neo4j",